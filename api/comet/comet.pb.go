@@ -275,6 +275,540 @@ func (m *BroadcastRoomReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_BroadcastRoomReply proto.InternalMessageInfo
 
+type KickReq struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Reason               int32    `protobuf:"varint,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickReq) Reset()         { *m = KickReq{} }
+func (m *KickReq) String() string { return proto.CompactTextString(m) }
+func (*KickReq) ProtoMessage()    {}
+func (*KickReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{6}
+}
+
+func (m *KickReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickReq.Unmarshal(m, b)
+}
+func (m *KickReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickReq.Marshal(b, m, deterministic)
+}
+func (m *KickReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickReq.Merge(m, src)
+}
+func (m *KickReq) XXX_Size() int {
+	return xxx_messageInfo_KickReq.Size(m)
+}
+func (m *KickReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickReq proto.InternalMessageInfo
+
+func (m *KickReq) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *KickReq) GetReason() int32 {
+	if m != nil {
+		return m.Reason
+	}
+	return 0
+}
+
+type KickReply struct {
+	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickReply) Reset()         { *m = KickReply{} }
+func (m *KickReply) String() string { return proto.CompactTextString(m) }
+func (*KickReply) ProtoMessage()    {}
+func (*KickReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{7}
+}
+
+func (m *KickReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickReply.Unmarshal(m, b)
+}
+func (m *KickReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickReply.Marshal(b, m, deterministic)
+}
+func (m *KickReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickReply.Merge(m, src)
+}
+func (m *KickReply) XXX_Size() int {
+	return xxx_messageInfo_KickReply.Size(m)
+}
+func (m *KickReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickReply proto.InternalMessageInfo
+
+func (m *KickReply) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type RoomMembersReq struct {
+	RoomID               string   `protobuf:"bytes,1,opt,name=roomID,proto3" json:"roomID,omitempty"`
+	Offset               int32    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoomMembersReq) Reset()         { *m = RoomMembersReq{} }
+func (m *RoomMembersReq) String() string { return proto.CompactTextString(m) }
+func (*RoomMembersReq) ProtoMessage()    {}
+func (*RoomMembersReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{8}
+}
+
+func (m *RoomMembersReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomMembersReq.Unmarshal(m, b)
+}
+func (m *RoomMembersReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomMembersReq.Marshal(b, m, deterministic)
+}
+func (m *RoomMembersReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomMembersReq.Merge(m, src)
+}
+func (m *RoomMembersReq) XXX_Size() int {
+	return xxx_messageInfo_RoomMembersReq.Size(m)
+}
+func (m *RoomMembersReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomMembersReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoomMembersReq proto.InternalMessageInfo
+
+func (m *RoomMembersReq) GetRoomID() string {
+	if m != nil {
+		return m.RoomID
+	}
+	return ""
+}
+
+func (m *RoomMembersReq) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *RoomMembersReq) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type RoomMember struct {
+	Mid                  int64    `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoomMember) Reset()         { *m = RoomMember{} }
+func (m *RoomMember) String() string { return proto.CompactTextString(m) }
+func (*RoomMember) ProtoMessage()    {}
+func (*RoomMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{9}
+}
+
+func (m *RoomMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomMember.Unmarshal(m, b)
+}
+func (m *RoomMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomMember.Marshal(b, m, deterministic)
+}
+func (m *RoomMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomMember.Merge(m, src)
+}
+func (m *RoomMember) XXX_Size() int {
+	return xxx_messageInfo_RoomMember.Size(m)
+}
+func (m *RoomMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoomMember proto.InternalMessageInfo
+
+func (m *RoomMember) GetMid() int64 {
+	if m != nil {
+		return m.Mid
+	}
+	return 0
+}
+
+func (m *RoomMember) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type RoomMembersReply struct {
+	Total                int32         `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Members              []*RoomMember `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RoomMembersReply) Reset()         { *m = RoomMembersReply{} }
+func (m *RoomMembersReply) String() string { return proto.CompactTextString(m) }
+func (*RoomMembersReply) ProtoMessage()    {}
+func (*RoomMembersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{10}
+}
+
+func (m *RoomMembersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomMembersReply.Unmarshal(m, b)
+}
+func (m *RoomMembersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomMembersReply.Marshal(b, m, deterministic)
+}
+func (m *RoomMembersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomMembersReply.Merge(m, src)
+}
+func (m *RoomMembersReply) XXX_Size() int {
+	return xxx_messageInfo_RoomMembersReply.Size(m)
+}
+func (m *RoomMembersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomMembersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoomMembersReply proto.InternalMessageInfo
+
+func (m *RoomMembersReply) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *RoomMembersReply) GetMembers() []*RoomMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type StatsReq struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsReq) Reset()         { *m = StatsReq{} }
+func (m *StatsReq) String() string { return proto.CompactTextString(m) }
+func (*StatsReq) ProtoMessage()    {}
+func (*StatsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{11}
+}
+
+func (m *StatsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsReq.Unmarshal(m, b)
+}
+func (m *StatsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsReq.Marshal(b, m, deterministic)
+}
+func (m *StatsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsReq.Merge(m, src)
+}
+func (m *StatsReq) XXX_Size() int {
+	return xxx_messageInfo_StatsReq.Size(m)
+}
+func (m *StatsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsReq proto.InternalMessageInfo
+
+type BucketStats struct {
+	Channels             int32    `protobuf:"varint,1,opt,name=channels,proto3" json:"channels,omitempty"`
+	Rooms                int32    `protobuf:"varint,2,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	Ips                  int32    `protobuf:"varint,3,opt,name=ips,proto3" json:"ips,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BucketStats) Reset()         { *m = BucketStats{} }
+func (m *BucketStats) String() string { return proto.CompactTextString(m) }
+func (*BucketStats) ProtoMessage()    {}
+func (*BucketStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{12}
+}
+
+func (m *BucketStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BucketStats.Unmarshal(m, b)
+}
+func (m *BucketStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BucketStats.Marshal(b, m, deterministic)
+}
+func (m *BucketStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BucketStats.Merge(m, src)
+}
+func (m *BucketStats) XXX_Size() int {
+	return xxx_messageInfo_BucketStats.Size(m)
+}
+func (m *BucketStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_BucketStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BucketStats proto.InternalMessageInfo
+
+func (m *BucketStats) GetChannels() int32 {
+	if m != nil {
+		return m.Channels
+	}
+	return 0
+}
+
+func (m *BucketStats) GetRooms() int32 {
+	if m != nil {
+		return m.Rooms
+	}
+	return 0
+}
+
+func (m *BucketStats) GetIps() int32 {
+	if m != nil {
+		return m.Ips
+	}
+	return 0
+}
+
+type StatsReply struct {
+	Buckets              []*BucketStats `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Channels             int64          `protobuf:"varint,2,opt,name=channels,proto3" json:"channels,omitempty"`
+	Rooms                int64          `protobuf:"varint,3,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	Ips                  int64          `protobuf:"varint,4,opt,name=ips,proto3" json:"ips,omitempty"`
+	SignalDropped        uint64         `protobuf:"varint,5,opt,name=signalDropped,proto3" json:"signalDropped,omitempty"`
+	Goroutines           int32          `protobuf:"varint,6,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	HeapAlloc            uint64         `protobuf:"varint,7,opt,name=heapAlloc,proto3" json:"heapAlloc,omitempty"`
+	HeapInuse            uint64         `protobuf:"varint,8,opt,name=heapInuse,proto3" json:"heapInuse,omitempty"`
+	Sys                  uint64         `protobuf:"varint,9,opt,name=sys,proto3" json:"sys,omitempty"`
+	NumGC                uint32         `protobuf:"varint,10,opt,name=numGC,proto3" json:"numGC,omitempty"`
+	StartTime            int64          `protobuf:"varint,11,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *StatsReply) Reset()         { *m = StatsReply{} }
+func (m *StatsReply) String() string { return proto.CompactTextString(m) }
+func (*StatsReply) ProtoMessage()    {}
+func (*StatsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{13}
+}
+
+func (m *StatsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsReply.Unmarshal(m, b)
+}
+func (m *StatsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsReply.Marshal(b, m, deterministic)
+}
+func (m *StatsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsReply.Merge(m, src)
+}
+func (m *StatsReply) XXX_Size() int {
+	return xxx_messageInfo_StatsReply.Size(m)
+}
+func (m *StatsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsReply proto.InternalMessageInfo
+
+func (m *StatsReply) GetBuckets() []*BucketStats {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
+}
+
+func (m *StatsReply) GetChannels() int64 {
+	if m != nil {
+		return m.Channels
+	}
+	return 0
+}
+
+func (m *StatsReply) GetRooms() int64 {
+	if m != nil {
+		return m.Rooms
+	}
+	return 0
+}
+
+func (m *StatsReply) GetIps() int64 {
+	if m != nil {
+		return m.Ips
+	}
+	return 0
+}
+
+func (m *StatsReply) GetSignalDropped() uint64 {
+	if m != nil {
+		return m.SignalDropped
+	}
+	return 0
+}
+
+func (m *StatsReply) GetGoroutines() int32 {
+	if m != nil {
+		return m.Goroutines
+	}
+	return 0
+}
+
+func (m *StatsReply) GetHeapAlloc() uint64 {
+	if m != nil {
+		return m.HeapAlloc
+	}
+	return 0
+}
+
+func (m *StatsReply) GetHeapInuse() uint64 {
+	if m != nil {
+		return m.HeapInuse
+	}
+	return 0
+}
+
+func (m *StatsReply) GetSys() uint64 {
+	if m != nil {
+		return m.Sys
+	}
+	return 0
+}
+
+func (m *StatsReply) GetNumGC() uint32 {
+	if m != nil {
+		return m.NumGC
+	}
+	return 0
+}
+
+func (m *StatsReply) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+type WhitelistReq struct {
+	Mids                 []int64  `protobuf:"varint,1,rep,packed,name=mids,proto3" json:"mids,omitempty"`
+	Prefixes             []string `protobuf:"bytes,2,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	Sample               int32    `protobuf:"varint,3,opt,name=sample,proto3" json:"sample,omitempty"`
+	Ttl                  int64    `protobuf:"varint,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WhitelistReq) Reset()         { *m = WhitelistReq{} }
+func (m *WhitelistReq) String() string { return proto.CompactTextString(m) }
+func (*WhitelistReq) ProtoMessage()    {}
+func (*WhitelistReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{14}
+}
+
+func (m *WhitelistReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WhitelistReq.Unmarshal(m, b)
+}
+func (m *WhitelistReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WhitelistReq.Marshal(b, m, deterministic)
+}
+func (m *WhitelistReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WhitelistReq.Merge(m, src)
+}
+func (m *WhitelistReq) XXX_Size() int {
+	return xxx_messageInfo_WhitelistReq.Size(m)
+}
+func (m *WhitelistReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_WhitelistReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WhitelistReq proto.InternalMessageInfo
+
+func (m *WhitelistReq) GetMids() []int64 {
+	if m != nil {
+		return m.Mids
+	}
+	return nil
+}
+
+func (m *WhitelistReq) GetPrefixes() []string {
+	if m != nil {
+		return m.Prefixes
+	}
+	return nil
+}
+
+func (m *WhitelistReq) GetSample() int32 {
+	if m != nil {
+		return m.Sample
+	}
+	return 0
+}
+
+func (m *WhitelistReq) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+type WhitelistReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WhitelistReply) Reset()         { *m = WhitelistReply{} }
+func (m *WhitelistReply) String() string { return proto.CompactTextString(m) }
+func (*WhitelistReply) ProtoMessage()    {}
+func (*WhitelistReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{15}
+}
+
+func (m *WhitelistReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WhitelistReply.Unmarshal(m, b)
+}
+func (m *WhitelistReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WhitelistReply.Marshal(b, m, deterministic)
+}
+func (m *WhitelistReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WhitelistReply.Merge(m, src)
+}
+func (m *WhitelistReply) XXX_Size() int {
+	return xxx_messageInfo_WhitelistReply.Size(m)
+}
+func (m *WhitelistReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_WhitelistReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WhitelistReply proto.InternalMessageInfo
+
 type RoomsReq struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -285,7 +819,7 @@ func (m *RoomsReq) Reset()         { *m = RoomsReq{} }
 func (m *RoomsReq) String() string { return proto.CompactTextString(m) }
 func (*RoomsReq) ProtoMessage()    {}
 func (*RoomsReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_327b4a7d084564be, []int{6}
+	return fileDescriptor_327b4a7d084564be, []int{16}
 }
 
 func (m *RoomsReq) XXX_Unmarshal(b []byte) error {
@@ -317,7 +851,7 @@ func (m *RoomsReply) Reset()         { *m = RoomsReply{} }
 func (m *RoomsReply) String() string { return proto.CompactTextString(m) }
 func (*RoomsReply) ProtoMessage()    {}
 func (*RoomsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_327b4a7d084564be, []int{7}
+	return fileDescriptor_327b4a7d084564be, []int{17}
 }
 
 func (m *RoomsReply) XXX_Unmarshal(b []byte) error {
@@ -345,48 +879,184 @@ func (m *RoomsReply) GetRooms() map[string]bool {
 	return nil
 }
 
+type ShedReq struct {
+	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Server               string   `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	Addrs                []string `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShedReq) Reset()         { *m = ShedReq{} }
+func (m *ShedReq) String() string { return proto.CompactTextString(m) }
+func (*ShedReq) ProtoMessage()    {}
+func (*ShedReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{18}
+}
+
+func (m *ShedReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShedReq.Unmarshal(m, b)
+}
+func (m *ShedReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShedReq.Marshal(b, m, deterministic)
+}
+func (m *ShedReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShedReq.Merge(m, src)
+}
+func (m *ShedReq) XXX_Size() int {
+	return xxx_messageInfo_ShedReq.Size(m)
+}
+func (m *ShedReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShedReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShedReq proto.InternalMessageInfo
+
+func (m *ShedReq) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ShedReq) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *ShedReq) GetAddrs() []string {
+	if m != nil {
+		return m.Addrs
+	}
+	return nil
+}
+
+type ShedReply struct {
+	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShedReply) Reset()         { *m = ShedReply{} }
+func (m *ShedReply) String() string { return proto.CompactTextString(m) }
+func (*ShedReply) ProtoMessage()    {}
+func (*ShedReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_327b4a7d084564be, []int{19}
+}
+
+func (m *ShedReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShedReply.Unmarshal(m, b)
+}
+func (m *ShedReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShedReply.Marshal(b, m, deterministic)
+}
+func (m *ShedReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShedReply.Merge(m, src)
+}
+func (m *ShedReply) XXX_Size() int {
+	return xxx_messageInfo_ShedReply.Size(m)
+}
+func (m *ShedReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShedReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShedReply proto.InternalMessageInfo
+
+func (m *ShedReply) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
 func init() {
-	proto.RegisterType((*PushMsgReq)(nil), "goim.comet.PushMsgReq")
-	proto.RegisterType((*PushMsgReply)(nil), "goim.comet.PushMsgReply")
-	proto.RegisterType((*BroadcastReq)(nil), "goim.comet.BroadcastReq")
-	proto.RegisterType((*BroadcastReply)(nil), "goim.comet.BroadcastReply")
-	proto.RegisterType((*BroadcastRoomReq)(nil), "goim.comet.BroadcastRoomReq")
-	proto.RegisterType((*BroadcastRoomReply)(nil), "goim.comet.BroadcastRoomReply")
-	proto.RegisterType((*RoomsReq)(nil), "goim.comet.RoomsReq")
-	proto.RegisterType((*RoomsReply)(nil), "goim.comet.RoomsReply")
-	proto.RegisterMapType((map[string]bool)(nil), "goim.comet.RoomsReply.RoomsEntry")
+	proto.RegisterType((*PushMsgReq)(nil), "mygoim.comet.PushMsgReq")
+	proto.RegisterType((*PushMsgReply)(nil), "mygoim.comet.PushMsgReply")
+	proto.RegisterType((*BroadcastReq)(nil), "mygoim.comet.BroadcastReq")
+	proto.RegisterType((*BroadcastReply)(nil), "mygoim.comet.BroadcastReply")
+	proto.RegisterType((*BroadcastRoomReq)(nil), "mygoim.comet.BroadcastRoomReq")
+	proto.RegisterType((*BroadcastRoomReply)(nil), "mygoim.comet.BroadcastRoomReply")
+	proto.RegisterType((*KickReq)(nil), "mygoim.comet.KickReq")
+	proto.RegisterType((*KickReply)(nil), "mygoim.comet.KickReply")
+	proto.RegisterType((*RoomMembersReq)(nil), "mygoim.comet.RoomMembersReq")
+	proto.RegisterType((*RoomMember)(nil), "mygoim.comet.RoomMember")
+	proto.RegisterType((*RoomMembersReply)(nil), "mygoim.comet.RoomMembersReply")
+	proto.RegisterType((*StatsReq)(nil), "mygoim.comet.StatsReq")
+	proto.RegisterType((*BucketStats)(nil), "mygoim.comet.BucketStats")
+	proto.RegisterType((*StatsReply)(nil), "mygoim.comet.StatsReply")
+	proto.RegisterType((*WhitelistReq)(nil), "mygoim.comet.WhitelistReq")
+	proto.RegisterType((*WhitelistReply)(nil), "mygoim.comet.WhitelistReply")
+	proto.RegisterType((*RoomsReq)(nil), "mygoim.comet.RoomsReq")
+	proto.RegisterType((*RoomsReply)(nil), "mygoim.comet.RoomsReply")
+	proto.RegisterMapType((map[string]bool)(nil), "mygoim.comet.RoomsReply.RoomsEntry")
+	proto.RegisterType((*ShedReq)(nil), "mygoim.comet.ShedReq")
+	proto.RegisterType((*ShedReply)(nil), "mygoim.comet.ShedReply")
 }
 
 func init() { proto.RegisterFile("comet/comet.proto", fileDescriptor_327b4a7d084564be) }
 
 var fileDescriptor_327b4a7d084564be = []byte{
-	// 404 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xcf, 0x4f, 0xe2, 0x40,
-	0x14, 0x4e, 0x61, 0xcb, 0x8f, 0x07, 0x4b, 0xd8, 0x49, 0x43, 0x9a, 0x66, 0xb3, 0x61, 0x7b, 0x62,
-	0xd7, 0xd8, 0x26, 0x18, 0x22, 0x91, 0x93, 0xa8, 0x07, 0x0f, 0x44, 0x32, 0x31, 0x1e, 0xbc, 0x15,
-	0x18, 0x01, 0x69, 0x9d, 0xd2, 0x16, 0x93, 0x9e, 0xfc, 0x0b, 0xfc, 0x9f, 0xcd, 0x9b, 0xa9, 0x6d,
-	0x31, 0x95, 0x44, 0x2f, 0xcd, 0xf7, 0xde, 0x7c, 0xf3, 0xbe, 0x6f, 0xbe, 0x07, 0xf0, 0x6b, 0xce,
-	0x3d, 0x16, 0xd9, 0xe2, 0x6b, 0xf9, 0x01, 0x8f, 0x38, 0x81, 0x25, 0x5f, 0x7b, 0x96, 0xe8, 0x18,
-	0x83, 0xe5, 0x3a, 0x5a, 0xed, 0x66, 0x58, 0xd9, 0xb7, 0x2c, 0x08, 0xe2, 0xe3, 0x89, 0xc3, 0x6d,
-	0x24, 0xd8, 0x8e, 0xbf, 0xb6, 0xc5, 0x85, 0x39, 0x77, 0x53, 0x20, 0x47, 0x98, 0x0f, 0x00, 0xd3,
-	0x5d, 0xb8, 0x9a, 0x84, 0x4b, 0xca, 0xb6, 0x84, 0xc0, 0x8f, 0x0d, 0x8b, 0x43, 0x5d, 0xe9, 0x96,
-	0x7b, 0x75, 0x2a, 0x30, 0xd1, 0xa1, 0x2a, 0xa8, 0x37, 0xbe, 0x5e, 0xee, 0x2a, 0x3d, 0x95, 0xbe,
-	0x97, 0xe4, 0x3f, 0xa8, 0x02, 0xea, 0xa5, 0xae, 0xd2, 0x6b, 0xf4, 0x35, 0x4b, 0xd8, 0x49, 0x05,
-	0xa6, 0x08, 0xa8, 0xa4, 0x98, 0x2d, 0x68, 0xa6, 0x3a, 0xbe, 0x1b, 0x9b, 0x8f, 0xd0, 0x1c, 0x07,
-	0xdc, 0x59, 0xcc, 0x9d, 0x30, 0x42, 0xe5, 0x9c, 0x8a, 0xf2, 0x6d, 0x15, 0xa2, 0x81, 0x1a, 0xfa,
-	0x8c, 0x2d, 0x12, 0xa7, 0xb2, 0x30, 0xdb, 0xd0, 0xca, 0x69, 0xa1, 0xfa, 0x1d, 0xb4, 0xb3, 0x0e,
-	0xe7, 0x1e, 0x3a, 0xe8, 0x40, 0x25, 0xe0, 0xdc, 0xbb, 0xbe, 0x14, 0x06, 0xea, 0x34, 0xa9, 0xbe,
-	0xf4, 0x4a, 0x0d, 0xc8, 0x87, 0xb9, 0xa8, 0x06, 0x50, 0xc3, 0x22, 0xa4, 0x6c, 0x6b, 0xbe, 0x00,
-	0x24, 0xd8, 0x77, 0x63, 0x72, 0x0a, 0x2a, 0xaa, 0xc8, 0xc0, 0x1b, 0xfd, 0xbf, 0x56, 0xb6, 0x50,
-	0x2b, 0xa3, 0x49, 0x78, 0xf5, 0x14, 0x05, 0x31, 0x95, 0x7c, 0x63, 0x98, 0x8c, 0x11, 0x4d, 0xd2,
-	0x86, 0xf2, 0x86, 0xc5, 0x89, 0x6f, 0x84, 0x18, 0xc4, 0xb3, 0xe3, 0xee, 0x98, 0x30, 0x5d, 0xa3,
-	0xb2, 0x38, 0x2b, 0x0d, 0x95, 0xfe, 0x6b, 0x09, 0xd4, 0x0b, 0x14, 0x20, 0x23, 0xa8, 0x26, 0x2b,
-	0x21, 0x9d, 0xbc, 0x70, 0xf6, 0x7b, 0x30, 0xf4, 0xc2, 0x3e, 0x3a, 0x3f, 0x87, 0x7a, 0xfa, 0x52,
-	0xb2, 0x47, 0xcb, 0xaf, 0xd5, 0x30, 0x3e, 0x39, 0xc1, 0x11, 0x13, 0xf8, 0xb9, 0x17, 0x16, 0xf9,
-	0x5d, 0x4c, 0x96, 0xfb, 0x31, 0xfe, 0x1c, 0x38, 0xc5, 0x71, 0x03, 0x50, 0x45, 0x24, 0x44, 0x2b,
-	0x48, 0x71, 0x6b, 0x74, 0x8a, 0xb3, 0x1d, 0x1f, 0xdd, 0xff, 0x3b, 0xfc, 0xcf, 0x11, 0x37, 0x46,
-	0xe2, 0x3b, 0xab, 0x88, 0x35, 0x9f, 0xbc, 0x05, 0x00, 0x00, 0xff, 0xff, 0xb3, 0xcb, 0xf3, 0x2a,
-	0x8c, 0x03, 0x00, 0x00,
+	// 891 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x6f, 0xe3, 0x44,
+	0x10, 0x97, 0xf3, 0xa7, 0x49, 0x26, 0x69, 0x55, 0x56, 0xa5, 0x67, 0xac, 0xaa, 0x0a, 0x86, 0x87,
+	0x00, 0x52, 0x72, 0xca, 0xe9, 0xfe, 0x00, 0x0f, 0xe8, 0x7a, 0x3d, 0xc1, 0xe9, 0x54, 0x71, 0xb7,
+	0x87, 0x0e, 0x09, 0xf1, 0xe2, 0xc6, 0xdb, 0x64, 0xe9, 0xda, 0xeb, 0xf3, 0x6e, 0x2a, 0xfc, 0xc8,
+	0xc7, 0xe1, 0xc3, 0xf0, 0x9d, 0xd0, 0xec, 0xae, 0x63, 0x27, 0xc4, 0x95, 0x40, 0xbc, 0x58, 0x33,
+	0xb3, 0xb3, 0xf3, 0xfb, 0xcd, 0x9f, 0x9d, 0x04, 0x3e, 0x5a, 0xc8, 0x84, 0xe9, 0x99, 0xf9, 0x4e,
+	0xb3, 0x5c, 0x6a, 0x49, 0x46, 0x49, 0xb1, 0x94, 0x3c, 0x99, 0x1a, 0x5b, 0xf0, 0x78, 0xc9, 0xf5,
+	0x6a, 0x7d, 0x8d, 0xda, 0x6c, 0x59, 0x14, 0x0f, 0x9f, 0xce, 0x9f, 0xce, 0xac, 0xc3, 0x2c, 0xca,
+	0xf8, 0xcc, 0x5c, 0x59, 0x48, 0xb1, 0x11, 0x6c, 0x90, 0xf0, 0x06, 0xe0, 0xcd, 0x5a, 0xad, 0xae,
+	0xd4, 0x92, 0xb2, 0x0f, 0x84, 0x40, 0xe7, 0x96, 0x15, 0xca, 0xf7, 0xc6, 0xed, 0xc9, 0x80, 0x1a,
+	0x99, 0xf8, 0xd0, 0x33, 0xae, 0x3f, 0x66, 0x7e, 0x7b, 0xec, 0x4d, 0xba, 0xb4, 0x54, 0xc9, 0x97,
+	0xd0, 0x35, 0xa2, 0xdf, 0x1a, 0x7b, 0x93, 0xe1, 0xfc, 0x64, 0x6a, 0xe8, 0x6c, 0x00, 0xde, 0xa0,
+	0x40, 0xad, 0x4b, 0x78, 0x04, 0xa3, 0x0d, 0x4e, 0x26, 0x8a, 0xf0, 0x37, 0x18, 0x5d, 0xe4, 0x32,
+	0x8a, 0x17, 0x91, 0xd2, 0x88, 0x5c, 0x43, 0xf1, 0xfe, 0x33, 0x0a, 0x39, 0x81, 0xae, 0xca, 0x18,
+	0x8b, 0x1d, 0x53, 0xab, 0x84, 0xc7, 0x70, 0x54, 0xc3, 0x42, 0xf4, 0xf7, 0x70, 0x5c, 0x59, 0xa4,
+	0x4c, 0x90, 0xc1, 0x29, 0x1c, 0xe4, 0x52, 0x26, 0xaf, 0x2e, 0x0d, 0x81, 0x01, 0x75, 0xda, 0xbf,
+	0xca, 0xf2, 0x04, 0xc8, 0x4e, 0x5c, 0x44, 0x7b, 0x0c, 0xbd, 0xd7, 0x7c, 0x71, 0xdb, 0x54, 0x60,
+	0x04, 0x66, 0x91, 0x92, 0xa9, 0x41, 0xe8, 0x52, 0xa7, 0x85, 0x9f, 0xc2, 0xc0, 0x5e, 0xcb, 0x44,
+	0x81, 0x99, 0x2d, 0xe4, 0x3a, 0xd5, 0xae, 0x3a, 0x56, 0x09, 0xdf, 0xc3, 0x11, 0xc2, 0x5c, 0xb1,
+	0xe4, 0x9a, 0xe5, 0xea, 0xbe, 0x2c, 0x4e, 0xe1, 0x40, 0xde, 0xdc, 0x28, 0xa6, 0x4b, 0x10, 0xab,
+	0x61, 0x5c, 0xc1, 0x13, 0xae, 0xcb, 0x8a, 0x19, 0x25, 0x7c, 0x08, 0x50, 0xc5, 0x25, 0xc7, 0xd0,
+	0x4e, 0x78, 0x6c, 0x02, 0xb6, 0x29, 0x8a, 0x68, 0xb9, 0x65, 0x85, 0x09, 0x35, 0xa0, 0x28, 0x86,
+	0xbf, 0xc2, 0xf1, 0x16, 0x13, 0xc7, 0x59, 0x4b, 0x1d, 0x89, 0x92, 0xb3, 0x51, 0xc8, 0x1c, 0x7a,
+	0x89, 0xf5, 0xf2, 0x5b, 0xe3, 0xf6, 0x64, 0x38, 0xf7, 0xa7, 0xf5, 0x41, 0x9e, 0x56, 0x61, 0x68,
+	0xe9, 0x18, 0x02, 0xf4, 0xdf, 0xe9, 0x48, 0x63, 0x86, 0xe1, 0x5b, 0x18, 0x5e, 0xac, 0x17, 0xb7,
+	0x4c, 0x1b, 0x0b, 0x09, 0xa0, 0xbf, 0x58, 0x45, 0x69, 0xca, 0x84, 0x72, 0x38, 0x1b, 0x1d, 0x09,
+	0x60, 0xfa, 0xca, 0xe5, 0x6c, 0x15, 0x24, 0xcf, 0x33, 0xe5, 0x12, 0x46, 0x31, 0xfc, 0xab, 0x05,
+	0xe0, 0xe2, 0x23, 0xef, 0x47, 0xd0, 0xbb, 0x36, 0x08, 0xb6, 0x4f, 0xc3, 0xf9, 0x27, 0xdb, 0x0c,
+	0x6b, 0xf0, 0xb4, 0xf4, 0xdc, 0xe2, 0xd1, 0x32, 0x95, 0xda, 0xc3, 0xa3, 0x6d, 0x0e, 0xb6, 0x79,
+	0x74, 0x6c, 0x59, 0x79, 0xa6, 0xc8, 0xe7, 0x70, 0xa8, 0xf8, 0x32, 0x8d, 0xc4, 0x65, 0x2e, 0xb3,
+	0x8c, 0xc5, 0x7e, 0x77, 0xec, 0x4d, 0x3a, 0x74, 0xdb, 0x48, 0xce, 0x01, 0x96, 0x32, 0x97, 0x6b,
+	0xcd, 0x53, 0xa6, 0xfc, 0x03, 0x93, 0x46, 0xcd, 0x42, 0xce, 0x60, 0xb0, 0x62, 0x51, 0xf6, 0x5c,
+	0x08, 0xb9, 0xf0, 0x7b, 0x26, 0x42, 0x65, 0x28, 0x4f, 0x5f, 0xa5, 0x6b, 0xc5, 0xfc, 0x7e, 0x75,
+	0x6a, 0x0c, 0xc8, 0x49, 0x15, 0xca, 0x1f, 0x18, 0x3b, 0x8a, 0xc8, 0x3d, 0x5d, 0x27, 0xdf, 0xbf,
+	0xf0, 0x61, 0xec, 0x4d, 0x0e, 0xa9, 0x55, 0x30, 0x8a, 0xd2, 0x51, 0xae, 0x7f, 0xe2, 0x09, 0xf3,
+	0x87, 0x26, 0x83, 0xca, 0x10, 0xae, 0x60, 0xf4, 0xf3, 0x8a, 0x6b, 0x26, 0xb8, 0x7d, 0xdc, 0x04,
+	0x3a, 0x09, 0x8f, 0x6d, 0x35, 0xdb, 0xd4, 0xc8, 0x58, 0xaf, 0x2c, 0x67, 0x37, 0xfc, 0x77, 0x66,
+	0xe7, 0x60, 0x40, 0x37, 0x3a, 0x0e, 0xab, 0x8a, 0x92, 0x4c, 0x30, 0xd7, 0x24, 0xa7, 0x21, 0x3b,
+	0xad, 0x45, 0x59, 0x31, 0xad, 0x05, 0x3e, 0xed, 0x1a, 0x12, 0x3e, 0x36, 0x80, 0x3e, 0x4e, 0x90,
+	0x19, 0x95, 0x3f, 0x3c, 0x3b, 0xc7, 0xae, 0xaf, 0x5f, 0x97, 0x6d, 0xb0, 0x5d, 0xfd, 0xec, 0x9f,
+	0x73, 0x67, 0x1d, 0xad, 0xf8, 0x32, 0xd5, 0x79, 0xe1, 0x7a, 0x15, 0x3c, 0x03, 0xa8, 0x8c, 0xe5,
+	0xf8, 0x7b, 0x9b, 0xf1, 0xc7, 0x2a, 0xdd, 0x45, 0x62, 0xcd, 0x4c, 0xeb, 0xfb, 0xd4, 0x2a, 0xdf,
+	0xb4, 0x9e, 0x79, 0xe1, 0x15, 0xf4, 0xde, 0xad, 0x58, 0x8c, 0x65, 0xd8, 0xfb, 0x86, 0x4d, 0xb2,
+	0x2c, 0xbf, 0x63, 0xb9, 0x7b, 0x4e, 0x4e, 0x43, 0xef, 0x28, 0x8e, 0x73, 0x1c, 0x1a, 0xac, 0x8e,
+	0x55, 0x70, 0x29, 0xd8, 0x70, 0x8d, 0x4b, 0x61, 0xfe, 0x67, 0x17, 0xba, 0x2f, 0x30, 0x25, 0xf2,
+	0x1d, 0xf4, 0xdc, 0xd2, 0x25, 0x3b, 0x8f, 0xac, 0xda, 0xf9, 0x41, 0xd0, 0x70, 0x82, 0x00, 0x2f,
+	0x61, 0xb0, 0xd9, 0x67, 0x64, 0xc7, 0xb1, 0xbe, 0xbe, 0x83, 0xb3, 0xc6, 0x33, 0x0c, 0xf3, 0x16,
+	0x0e, 0xb7, 0xd6, 0x22, 0x39, 0x6f, 0x72, 0xb7, 0xbb, 0x38, 0x18, 0xdf, 0x7b, 0xee, 0x7a, 0x49,
+	0xcd, 0x2b, 0x3a, 0xdd, 0xdb, 0xc5, 0x0f, 0x81, 0xdf, 0xd4, 0x5d, 0xf2, 0x04, 0x3a, 0xb8, 0x57,
+	0xc9, 0xc7, 0xdb, 0x1e, 0x6e, 0x45, 0x07, 0x0f, 0xf6, 0x99, 0xf1, 0xde, 0x6b, 0x18, 0xd6, 0x56,
+	0x1c, 0x39, 0x6b, 0x5a, 0x5b, 0x06, 0xfe, 0xfc, 0x9e, 0x53, 0xc7, 0xdf, 0xee, 0xaf, 0x1d, 0xfe,
+	0xe5, 0x9a, 0x0b, 0xfc, 0xbd, 0x76, 0xbc, 0xfa, 0x03, 0x8c, 0x9e, 0xc7, 0xf1, 0x66, 0xec, 0x77,
+	0xfb, 0x52, 0x7f, 0x79, 0xc1, 0x59, 0xe3, 0x99, 0x8b, 0x74, 0xc9, 0xc4, 0xff, 0x11, 0xe9, 0x09,
+	0x74, 0x70, 0x2c, 0x77, 0x6b, 0xea, 0x26, 0x3f, 0x78, 0xb0, 0xcf, 0x9c, 0x89, 0xe2, 0xe2, 0xab,
+	0x5f, 0xbe, 0xb8, 0xff, 0x7f, 0x8b, 0xb9, 0xf0, 0xad, 0xf9, 0x5e, 0x1f, 0x98, 0x1f, 0xd9, 0x47,
+	0x7f, 0x0f, 0x00, 0x84, 0x98, 0x51, 0x5f, 0x0c, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -409,6 +1079,18 @@ type CometClient interface {
 	BroadcastRoom(ctx context.Context, in *BroadcastRoomReq, opts ...grpc.CallOption) (*BroadcastRoomReply, error)
 	// Rooms get all rooms
 	Rooms(ctx context.Context, in *RoomsReq, opts ...grpc.CallOption) (*RoomsReply, error)
+	// Kick close conns of the keys
+	Kick(ctx context.Context, in *KickReq, opts ...grpc.CallOption) (*KickReply, error)
+	// RoomMembers list conns in the room
+	RoomMembers(ctx context.Context, in *RoomMembersReq, opts ...grpc.CallOption) (*RoomMembersReply, error)
+	// Stats get conn, room and runtime stats of the server
+	Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatsReply, error)
+	// AddWhitelist add mids or key prefixes to the whitelist
+	AddWhitelist(ctx context.Context, in *WhitelistReq, opts ...grpc.CallOption) (*WhitelistReply, error)
+	// DelWhitelist remove mids or key prefixes from the whitelist
+	DelWhitelist(ctx context.Context, in *WhitelistReq, opts ...grpc.CallOption) (*WhitelistReply, error)
+	// Shed redirect some conns to another server for rebalancing
+	Shed(ctx context.Context, in *ShedReq, opts ...grpc.CallOption) (*ShedReply, error)
 }
 
 type cometClient struct {
@@ -421,7 +1103,7 @@ func NewCometClient(cc *grpc.ClientConn) CometClient {
 
 func (c *cometClient) PushMsg(ctx context.Context, in *PushMsgReq, opts ...grpc.CallOption) (*PushMsgReply, error) {
 	out := new(PushMsgReply)
-	err := c.cc.Invoke(ctx, "/goim.comet.Comet/PushMsg", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/PushMsg", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -430,7 +1112,7 @@ func (c *cometClient) PushMsg(ctx context.Context, in *PushMsgReq, opts ...grpc.
 
 func (c *cometClient) Broadcast(ctx context.Context, in *BroadcastReq, opts ...grpc.CallOption) (*BroadcastReply, error) {
 	out := new(BroadcastReply)
-	err := c.cc.Invoke(ctx, "/goim.comet.Comet/Broadcast", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/Broadcast", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -439,7 +1121,7 @@ func (c *cometClient) Broadcast(ctx context.Context, in *BroadcastReq, opts ...g
 
 func (c *cometClient) BroadcastRoom(ctx context.Context, in *BroadcastRoomReq, opts ...grpc.CallOption) (*BroadcastRoomReply, error) {
 	out := new(BroadcastRoomReply)
-	err := c.cc.Invoke(ctx, "/goim.comet.Comet/BroadcastRoom", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/BroadcastRoom", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -448,7 +1130,61 @@ func (c *cometClient) BroadcastRoom(ctx context.Context, in *BroadcastRoomReq, o
 
 func (c *cometClient) Rooms(ctx context.Context, in *RoomsReq, opts ...grpc.CallOption) (*RoomsReply, error) {
 	out := new(RoomsReply)
-	err := c.cc.Invoke(ctx, "/goim.comet.Comet/Rooms", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/Rooms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) Kick(ctx context.Context, in *KickReq, opts ...grpc.CallOption) (*KickReply, error) {
+	out := new(KickReply)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/Kick", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) RoomMembers(ctx context.Context, in *RoomMembersReq, opts ...grpc.CallOption) (*RoomMembersReply, error) {
+	out := new(RoomMembersReply)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/RoomMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatsReply, error) {
+	out := new(StatsReply)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) AddWhitelist(ctx context.Context, in *WhitelistReq, opts ...grpc.CallOption) (*WhitelistReply, error) {
+	out := new(WhitelistReply)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/AddWhitelist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) DelWhitelist(ctx context.Context, in *WhitelistReq, opts ...grpc.CallOption) (*WhitelistReply, error) {
+	out := new(WhitelistReply)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/DelWhitelist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cometClient) Shed(ctx context.Context, in *ShedReq, opts ...grpc.CallOption) (*ShedReply, error) {
+	out := new(ShedReply)
+	err := c.cc.Invoke(ctx, "/mygoim.comet.Comet/Shed", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -465,6 +1201,18 @@ type CometServer interface {
 	BroadcastRoom(context.Context, *BroadcastRoomReq) (*BroadcastRoomReply, error)
 	// Rooms get all rooms
 	Rooms(context.Context, *RoomsReq) (*RoomsReply, error)
+	// Kick close conns of the keys
+	Kick(context.Context, *KickReq) (*KickReply, error)
+	// RoomMembers list conns in the room
+	RoomMembers(context.Context, *RoomMembersReq) (*RoomMembersReply, error)
+	// Stats get conn, room and runtime stats of the server
+	Stats(context.Context, *StatsReq) (*StatsReply, error)
+	// AddWhitelist add mids or key prefixes to the whitelist
+	AddWhitelist(context.Context, *WhitelistReq) (*WhitelistReply, error)
+	// DelWhitelist remove mids or key prefixes from the whitelist
+	DelWhitelist(context.Context, *WhitelistReq) (*WhitelistReply, error)
+	// Shed redirect some conns to another server for rebalancing
+	Shed(context.Context, *ShedReq) (*ShedReply, error)
 }
 
 // UnimplementedCometServer can be embedded to have forward compatible implementations.
@@ -483,6 +1231,24 @@ func (*UnimplementedCometServer) BroadcastRoom(ctx context.Context, req *Broadca
 func (*UnimplementedCometServer) Rooms(ctx context.Context, req *RoomsReq) (*RoomsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Rooms not implemented")
 }
+func (*UnimplementedCometServer) Kick(ctx context.Context, req *KickReq) (*KickReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kick not implemented")
+}
+func (*UnimplementedCometServer) RoomMembers(ctx context.Context, req *RoomMembersReq) (*RoomMembersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RoomMembers not implemented")
+}
+func (*UnimplementedCometServer) Stats(ctx context.Context, req *StatsReq) (*StatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (*UnimplementedCometServer) AddWhitelist(ctx context.Context, req *WhitelistReq) (*WhitelistReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWhitelist not implemented")
+}
+func (*UnimplementedCometServer) DelWhitelist(ctx context.Context, req *WhitelistReq) (*WhitelistReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelWhitelist not implemented")
+}
+func (*UnimplementedCometServer) Shed(ctx context.Context, req *ShedReq) (*ShedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shed not implemented")
+}
 
 func RegisterCometServer(s *grpc.Server, srv CometServer) {
 	s.RegisterService(&_Comet_serviceDesc, srv)
@@ -498,7 +1264,7 @@ func _Comet_PushMsg_Handler(srv interface{}, ctx context.Context, dec func(inter
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/goim.comet.Comet/PushMsg",
+		FullMethod: "/mygoim.comet.Comet/PushMsg",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(CometServer).PushMsg(ctx, req.(*PushMsgReq))
@@ -516,7 +1282,7 @@ func _Comet_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(int
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/goim.comet.Comet/Broadcast",
+		FullMethod: "/mygoim.comet.Comet/Broadcast",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(CometServer).Broadcast(ctx, req.(*BroadcastReq))
@@ -534,7 +1300,7 @@ func _Comet_BroadcastRoom_Handler(srv interface{}, ctx context.Context, dec func
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/goim.comet.Comet/BroadcastRoom",
+		FullMethod: "/mygoim.comet.Comet/BroadcastRoom",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(CometServer).BroadcastRoom(ctx, req.(*BroadcastRoomReq))
@@ -552,7 +1318,7 @@ func _Comet_Rooms_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/goim.comet.Comet/Rooms",
+		FullMethod: "/mygoim.comet.Comet/Rooms",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(CometServer).Rooms(ctx, req.(*RoomsReq))
@@ -560,8 +1326,116 @@ func _Comet_Rooms_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Comet_Kick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).Kick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mygoim.comet.Comet/Kick",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).Kick(ctx, req.(*KickReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Comet_RoomMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomMembersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).RoomMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mygoim.comet.Comet/RoomMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).RoomMembers(ctx, req.(*RoomMembersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Comet_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mygoim.comet.Comet/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).Stats(ctx, req.(*StatsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Comet_AddWhitelist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WhitelistReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).AddWhitelist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mygoim.comet.Comet/AddWhitelist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).AddWhitelist(ctx, req.(*WhitelistReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Comet_DelWhitelist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WhitelistReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).DelWhitelist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mygoim.comet.Comet/DelWhitelist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).DelWhitelist(ctx, req.(*WhitelistReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Comet_Shed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CometServer).Shed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mygoim.comet.Comet/Shed",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CometServer).Shed(ctx, req.(*ShedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Comet_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "goim.comet.Comet",
+	ServiceName: "mygoim.comet.Comet",
 	HandlerType: (*CometServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
@@ -580,6 +1454,30 @@ var _Comet_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Rooms",
 			Handler:    _Comet_Rooms_Handler,
 		},
+		{
+			MethodName: "Kick",
+			Handler:    _Comet_Kick_Handler,
+		},
+		{
+			MethodName: "RoomMembers",
+			Handler:    _Comet_RoomMembers_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Comet_Stats_Handler,
+		},
+		{
+			MethodName: "AddWhitelist",
+			Handler:    _Comet_AddWhitelist_Handler,
+		},
+		{
+			MethodName: "DelWhitelist",
+			Handler:    _Comet_DelWhitelist_Handler,
+		},
+		{
+			MethodName: "Shed",
+			Handler:    _Comet_Shed_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "comet/comet.proto",
@@ -31,18 +31,21 @@ const (
 	PushMsg_PUSH      PushMsg_Type = 0
 	PushMsg_ROOM      PushMsg_Type = 1
 	PushMsg_BROADCAST PushMsg_Type = 2
+	PushMsg_KICK      PushMsg_Type = 3
 )
 
 var PushMsg_Type_name = map[int32]string{
 	0: "PUSH",
 	1: "ROOM",
 	2: "BROADCAST",
+	3: "KICK",
 }
 
 var PushMsg_Type_value = map[string]int32{
 	"PUSH":      0,
 	"ROOM":      1,
 	"BROADCAST": 2,
+	"KICK":      3,
 }
 
 func (x PushMsg_Type) String() string {
@@ -61,6 +64,8 @@ type PushMsg struct {
 	Room                 string       `protobuf:"bytes,5,opt,name=room,proto3" json:"room,omitempty"`
 	Keys                 []string     `protobuf:"bytes,6,rep,name=keys,proto3" json:"keys,omitempty"`
 	Msg                  []byte       `protobuf:"bytes,7,opt,name=msg,proto3" json:"msg,omitempty"`
+	Seq                  int32        `protobuf:"varint,8,opt,name=seq,proto3" json:"seq,omitempty"`
+	Reason               int32        `protobuf:"varint,9,opt,name=reason,proto3" json:"reason,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -140,10 +145,183 @@ func (m *PushMsg) GetMsg() []byte {
 	return nil
 }
 
+func (m *PushMsg) GetSeq() int32 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *PushMsg) GetReason() int32 {
+	if m != nil {
+		return m.Reason
+	}
+	return 0
+}
+
+type UpstreamMsg struct {
+	Mid                  int64    `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string   `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	Op                   int32    `protobuf:"varint,4,opt,name=op,proto3" json:"op,omitempty"`
+	Seq                  int32    `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`
+	Body                 []byte   `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Ts                   int64    `protobuf:"varint,7,opt,name=ts,proto3" json:"ts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpstreamMsg) Reset()         { *m = UpstreamMsg{} }
+func (m *UpstreamMsg) String() string { return proto.CompactTextString(m) }
+func (*UpstreamMsg) ProtoMessage()    {}
+func (*UpstreamMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{1}
+}
+
+func (m *UpstreamMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpstreamMsg.Unmarshal(m, b)
+}
+func (m *UpstreamMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpstreamMsg.Marshal(b, m, deterministic)
+}
+func (m *UpstreamMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpstreamMsg.Merge(m, src)
+}
+func (m *UpstreamMsg) XXX_Size() int {
+	return xxx_messageInfo_UpstreamMsg.Size(m)
+}
+func (m *UpstreamMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpstreamMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpstreamMsg proto.InternalMessageInfo
+
+func (m *UpstreamMsg) GetMid() int64 {
+	if m != nil {
+		return m.Mid
+	}
+	return 0
+}
+
+func (m *UpstreamMsg) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *UpstreamMsg) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *UpstreamMsg) GetOp() int32 {
+	if m != nil {
+		return m.Op
+	}
+	return 0
+}
+
+func (m *UpstreamMsg) GetSeq() int32 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *UpstreamMsg) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+func (m *UpstreamMsg) GetTs() int64 {
+	if m != nil {
+		return m.Ts
+	}
+	return 0
+}
+
+type PresenceEvent struct {
+	Mid                  int64    `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Online               bool     `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	Key                  string   `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string   `protobuf:"bytes,4,opt,name=server,proto3" json:"server,omitempty"`
+	Ts                   int64    `protobuf:"varint,5,opt,name=ts,proto3" json:"ts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PresenceEvent) Reset()         { *m = PresenceEvent{} }
+func (m *PresenceEvent) String() string { return proto.CompactTextString(m) }
+func (*PresenceEvent) ProtoMessage()    {}
+func (*PresenceEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{2}
+}
+
+func (m *PresenceEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PresenceEvent.Unmarshal(m, b)
+}
+func (m *PresenceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PresenceEvent.Marshal(b, m, deterministic)
+}
+func (m *PresenceEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PresenceEvent.Merge(m, src)
+}
+func (m *PresenceEvent) XXX_Size() int {
+	return xxx_messageInfo_PresenceEvent.Size(m)
+}
+func (m *PresenceEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_PresenceEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PresenceEvent proto.InternalMessageInfo
+
+func (m *PresenceEvent) GetMid() int64 {
+	if m != nil {
+		return m.Mid
+	}
+	return 0
+}
+
+func (m *PresenceEvent) GetOnline() bool {
+	if m != nil {
+		return m.Online
+	}
+	return false
+}
+
+func (m *PresenceEvent) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PresenceEvent) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *PresenceEvent) GetTs() int64 {
+	if m != nil {
+		return m.Ts
+	}
+	return 0
+}
+
 type ConnectReq struct {
 	Server               string   `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
 	Cookie               string   `protobuf:"bytes,2,opt,name=cookie,proto3" json:"cookie,omitempty"`
 	Token                []byte   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Ip                   string   `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -153,7 +331,7 @@ func (m *ConnectReq) Reset()         { *m = ConnectReq{} }
 func (m *ConnectReq) String() string { return proto.CompactTextString(m) }
 func (*ConnectReq) ProtoMessage()    {}
 func (*ConnectReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{1}
+	return fileDescriptor_2dfb3aef05fe3328, []int{3}
 }
 
 func (m *ConnectReq) XXX_Unmarshal(b []byte) error {
@@ -195,6 +373,13 @@ func (m *ConnectReq) GetToken() []byte {
 	return nil
 }
 
+func (m *ConnectReq) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
 type ConnectReply struct {
 	Mid                  int64    `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
 	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
@@ -210,7 +395,7 @@ func (m *ConnectReply) Reset()         { *m = ConnectReply{} }
 func (m *ConnectReply) String() string { return proto.CompactTextString(m) }
 func (*ConnectReply) ProtoMessage()    {}
 func (*ConnectReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{2}
+	return fileDescriptor_2dfb3aef05fe3328, []int{4}
 }
 
 func (m *ConnectReply) XXX_Unmarshal(b []byte) error {
@@ -279,7 +464,7 @@ func (m *DisconnectReq) Reset()         { *m = DisconnectReq{} }
 func (m *DisconnectReq) String() string { return proto.CompactTextString(m) }
 func (*DisconnectReq) ProtoMessage()    {}
 func (*DisconnectReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{3}
+	return fileDescriptor_2dfb3aef05fe3328, []int{5}
 }
 
 func (m *DisconnectReq) XXX_Unmarshal(b []byte) error {
@@ -332,7 +517,7 @@ func (m *DisconnectReply) Reset()         { *m = DisconnectReply{} }
 func (m *DisconnectReply) String() string { return proto.CompactTextString(m) }
 func (*DisconnectReply) ProtoMessage()    {}
 func (*DisconnectReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{4}
+	return fileDescriptor_2dfb3aef05fe3328, []int{6}
 }
 
 func (m *DisconnectReply) XXX_Unmarshal(b []byte) error {
@@ -373,7 +558,7 @@ func (m *HeartbeatReq) Reset()         { *m = HeartbeatReq{} }
 func (m *HeartbeatReq) String() string { return proto.CompactTextString(m) }
 func (*HeartbeatReq) ProtoMessage()    {}
 func (*HeartbeatReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{5}
+	return fileDescriptor_2dfb3aef05fe3328, []int{7}
 }
 
 func (m *HeartbeatReq) XXX_Unmarshal(b []byte) error {
@@ -425,7 +610,7 @@ func (m *HeartbeatReply) Reset()         { *m = HeartbeatReply{} }
 func (m *HeartbeatReply) String() string { return proto.CompactTextString(m) }
 func (*HeartbeatReply) ProtoMessage()    {}
 func (*HeartbeatReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{6}
+	return fileDescriptor_2dfb3aef05fe3328, []int{8}
 }
 
 func (m *HeartbeatReply) XXX_Unmarshal(b []byte) error {
@@ -458,7 +643,7 @@ func (m *OnlineReq) Reset()         { *m = OnlineReq{} }
 func (m *OnlineReq) String() string { return proto.CompactTextString(m) }
 func (*OnlineReq) ProtoMessage()    {}
 func (*OnlineReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{7}
+	return fileDescriptor_2dfb3aef05fe3328, []int{9}
 }
 
 func (m *OnlineReq) XXX_Unmarshal(b []byte) error {
@@ -504,7 +689,7 @@ func (m *OnlineReply) Reset()         { *m = OnlineReply{} }
 func (m *OnlineReply) String() string { return proto.CompactTextString(m) }
 func (*OnlineReply) ProtoMessage()    {}
 func (*OnlineReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{8}
+	return fileDescriptor_2dfb3aef05fe3328, []int{10}
 }
 
 func (m *OnlineReply) XXX_Unmarshal(b []byte) error {
@@ -535,6 +720,8 @@ func (m *OnlineReply) GetAllRoomCount() map[string]int32 {
 type ReceiveReq struct {
 	Mid                  int64           `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
 	Proto                *protocol.Proto `protobuf:"bytes,2,opt,name=proto,proto3" json:"proto,omitempty"`
+	Key                  string          `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string          `protobuf:"bytes,4,opt,name=server,proto3" json:"server,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -544,7 +731,7 @@ func (m *ReceiveReq) Reset()         { *m = ReceiveReq{} }
 func (m *ReceiveReq) String() string { return proto.CompactTextString(m) }
 func (*ReceiveReq) ProtoMessage()    {}
 func (*ReceiveReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{9}
+	return fileDescriptor_2dfb3aef05fe3328, []int{11}
 }
 
 func (m *ReceiveReq) XXX_Unmarshal(b []byte) error {
@@ -579,6 +766,20 @@ func (m *ReceiveReq) GetProto() *protocol.Proto {
 	return nil
 }
 
+func (m *ReceiveReq) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ReceiveReq) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
 type ReceiveReply struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -589,7 +790,7 @@ func (m *ReceiveReply) Reset()         { *m = ReceiveReply{} }
 func (m *ReceiveReply) String() string { return proto.CompactTextString(m) }
 func (*ReceiveReply) ProtoMessage()    {}
 func (*ReceiveReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{10}
+	return fileDescriptor_2dfb3aef05fe3328, []int{12}
 }
 
 func (m *ReceiveReply) XXX_Unmarshal(b []byte) error {
@@ -610,207 +811,1070 @@ func (m *ReceiveReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ReceiveReply proto.InternalMessageInfo
 
-type NodesReq struct {
-	Platform             string   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
-	ClientIP             string   `protobuf:"bytes,2,opt,name=clientIP,proto3" json:"clientIP,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type UnackedReq struct {
+	Mid                  int64             `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Key                  string            `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string            `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	Protos               []*protocol.Proto `protobuf:"bytes,4,rep,name=protos,proto3" json:"protos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func (m *NodesReq) Reset()         { *m = NodesReq{} }
-func (m *NodesReq) String() string { return proto.CompactTextString(m) }
-func (*NodesReq) ProtoMessage()    {}
-func (*NodesReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{11}
+func (m *UnackedReq) Reset()         { *m = UnackedReq{} }
+func (m *UnackedReq) String() string { return proto.CompactTextString(m) }
+func (*UnackedReq) ProtoMessage()    {}
+func (*UnackedReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{13}
 }
 
-func (m *NodesReq) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NodesReq.Unmarshal(m, b)
+func (m *UnackedReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnackedReq.Unmarshal(m, b)
 }
-func (m *NodesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NodesReq.Marshal(b, m, deterministic)
+func (m *UnackedReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnackedReq.Marshal(b, m, deterministic)
 }
-func (m *NodesReq) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NodesReq.Merge(m, src)
+func (m *UnackedReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnackedReq.Merge(m, src)
 }
-func (m *NodesReq) XXX_Size() int {
-	return xxx_messageInfo_NodesReq.Size(m)
+func (m *UnackedReq) XXX_Size() int {
+	return xxx_messageInfo_UnackedReq.Size(m)
 }
-func (m *NodesReq) XXX_DiscardUnknown() {
-	xxx_messageInfo_NodesReq.DiscardUnknown(m)
+func (m *UnackedReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnackedReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NodesReq proto.InternalMessageInfo
+var xxx_messageInfo_UnackedReq proto.InternalMessageInfo
 
-func (m *NodesReq) GetPlatform() string {
+func (m *UnackedReq) GetMid() int64 {
 	if m != nil {
-		return m.Platform
+		return m.Mid
+	}
+	return 0
+}
+
+func (m *UnackedReq) GetKey() string {
+	if m != nil {
+		return m.Key
 	}
 	return ""
 }
 
-func (m *NodesReq) GetClientIP() string {
+func (m *UnackedReq) GetServer() string {
 	if m != nil {
-		return m.ClientIP
+		return m.Server
 	}
 	return ""
 }
 
-type NodesReply struct {
-	Domain               string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
-	TcpPort              int32    `protobuf:"varint,2,opt,name=tcp_port,json=tcpPort,proto3" json:"tcp_port,omitempty"`
-	WsPort               int32    `protobuf:"varint,3,opt,name=ws_port,json=wsPort,proto3" json:"ws_port,omitempty"`
-	WssPort              int32    `protobuf:"varint,4,opt,name=wss_port,json=wssPort,proto3" json:"wss_port,omitempty"`
-	Heartbeat            int32    `protobuf:"varint,5,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
-	Nodes                []string `protobuf:"bytes,6,rep,name=nodes,proto3" json:"nodes,omitempty"`
-	Backoff              *Backoff `protobuf:"bytes,7,opt,name=backoff,proto3" json:"backoff,omitempty"`
-	HeartbeatMax         int32    `protobuf:"varint,8,opt,name=heartbeat_max,json=heartbeatMax,proto3" json:"heartbeat_max,omitempty"`
+func (m *UnackedReq) GetProtos() []*protocol.Proto {
+	if m != nil {
+		return m.Protos
+	}
+	return nil
+}
+
+type UnackedReply struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *NodesReply) Reset()         { *m = NodesReply{} }
-func (m *NodesReply) String() string { return proto.CompactTextString(m) }
-func (*NodesReply) ProtoMessage()    {}
-func (*NodesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{12}
+func (m *UnackedReply) Reset()         { *m = UnackedReply{} }
+func (m *UnackedReply) String() string { return proto.CompactTextString(m) }
+func (*UnackedReply) ProtoMessage()    {}
+func (*UnackedReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{14}
 }
 
-func (m *NodesReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_NodesReply.Unmarshal(m, b)
+func (m *UnackedReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnackedReply.Unmarshal(m, b)
 }
-func (m *NodesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NodesReply.Marshal(b, m, deterministic)
+func (m *UnackedReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnackedReply.Marshal(b, m, deterministic)
 }
-func (m *NodesReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NodesReply.Merge(m, src)
+func (m *UnackedReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnackedReply.Merge(m, src)
 }
-func (m *NodesReply) XXX_Size() int {
-	return xxx_messageInfo_NodesReply.Size(m)
+func (m *UnackedReply) XXX_Size() int {
+	return xxx_messageInfo_UnackedReply.Size(m)
 }
-func (m *NodesReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_NodesReply.DiscardUnknown(m)
+func (m *UnackedReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnackedReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NodesReply proto.InternalMessageInfo
+var xxx_messageInfo_UnackedReply proto.InternalMessageInfo
 
-func (m *NodesReply) GetDomain() string {
-	if m != nil {
-		return m.Domain
-	}
-	return ""
+type SyncReq struct {
+	Mid                  int64    `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string   `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	Seq                  int32    `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *NodesReply) GetTcpPort() int32 {
-	if m != nil {
-		return m.TcpPort
-	}
-	return 0
+func (m *SyncReq) Reset()         { *m = SyncReq{} }
+func (m *SyncReq) String() string { return proto.CompactTextString(m) }
+func (*SyncReq) ProtoMessage()    {}
+func (*SyncReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{15}
 }
 
-func (m *NodesReply) GetWsPort() int32 {
-	if m != nil {
-		return m.WsPort
-	}
-	return 0
+func (m *SyncReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncReq.Unmarshal(m, b)
+}
+func (m *SyncReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncReq.Marshal(b, m, deterministic)
+}
+func (m *SyncReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncReq.Merge(m, src)
+}
+func (m *SyncReq) XXX_Size() int {
+	return xxx_messageInfo_SyncReq.Size(m)
+}
+func (m *SyncReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncReq.DiscardUnknown(m)
 }
 
-func (m *NodesReply) GetWssPort() int32 {
+var xxx_messageInfo_SyncReq proto.InternalMessageInfo
+
+func (m *SyncReq) GetMid() int64 {
 	if m != nil {
-		return m.WssPort
+		return m.Mid
 	}
 	return 0
 }
 
-func (m *NodesReply) GetHeartbeat() int32 {
+func (m *SyncReq) GetKey() string {
 	if m != nil {
-		return m.Heartbeat
+		return m.Key
 	}
-	return 0
+	return ""
 }
 
-func (m *NodesReply) GetNodes() []string {
+func (m *SyncReq) GetServer() string {
 	if m != nil {
-		return m.Nodes
+		return m.Server
 	}
-	return nil
+	return ""
 }
 
-func (m *NodesReply) GetBackoff() *Backoff {
+func (m *SyncReq) GetSeq() int32 {
 	if m != nil {
-		return m.Backoff
+		return m.Seq
 	}
-	return nil
+	return 0
 }
 
-func (m *NodesReply) GetHeartbeatMax() int32 {
+type SyncReply struct {
+	Protos               []*protocol.Proto `protobuf:"bytes,1,rep,name=protos,proto3" json:"protos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SyncReply) Reset()         { *m = SyncReply{} }
+func (m *SyncReply) String() string { return proto.CompactTextString(m) }
+func (*SyncReply) ProtoMessage()    {}
+func (*SyncReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{16}
+}
+
+func (m *SyncReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncReply.Unmarshal(m, b)
+}
+func (m *SyncReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncReply.Marshal(b, m, deterministic)
+}
+func (m *SyncReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncReply.Merge(m, src)
+}
+func (m *SyncReply) XXX_Size() int {
+	return xxx_messageInfo_SyncReply.Size(m)
+}
+func (m *SyncReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncReply proto.InternalMessageInfo
+
+func (m *SyncReply) GetProtos() []*protocol.Proto {
+	if m != nil {
+		return m.Protos
+	}
+	return nil
+}
+
+type RoomHistoryReq struct {
+	Room                 string   `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoomHistoryReq) Reset()         { *m = RoomHistoryReq{} }
+func (m *RoomHistoryReq) String() string { return proto.CompactTextString(m) }
+func (*RoomHistoryReq) ProtoMessage()    {}
+func (*RoomHistoryReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{17}
+}
+
+func (m *RoomHistoryReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomHistoryReq.Unmarshal(m, b)
+}
+func (m *RoomHistoryReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomHistoryReq.Marshal(b, m, deterministic)
+}
+func (m *RoomHistoryReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomHistoryReq.Merge(m, src)
+}
+func (m *RoomHistoryReq) XXX_Size() int {
+	return xxx_messageInfo_RoomHistoryReq.Size(m)
+}
+func (m *RoomHistoryReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomHistoryReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoomHistoryReq proto.InternalMessageInfo
+
+func (m *RoomHistoryReq) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *RoomHistoryReq) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type RoomHistoryReply struct {
+	Protos               []*protocol.Proto `protobuf:"bytes,1,rep,name=protos,proto3" json:"protos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RoomHistoryReply) Reset()         { *m = RoomHistoryReply{} }
+func (m *RoomHistoryReply) String() string { return proto.CompactTextString(m) }
+func (*RoomHistoryReply) ProtoMessage()    {}
+func (*RoomHistoryReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{18}
+}
+
+func (m *RoomHistoryReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomHistoryReply.Unmarshal(m, b)
+}
+func (m *RoomHistoryReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomHistoryReply.Marshal(b, m, deterministic)
+}
+func (m *RoomHistoryReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomHistoryReply.Merge(m, src)
+}
+func (m *RoomHistoryReply) XXX_Size() int {
+	return xxx_messageInfo_RoomHistoryReply.Size(m)
+}
+func (m *RoomHistoryReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomHistoryReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoomHistoryReply proto.InternalMessageInfo
+
+func (m *RoomHistoryReply) GetProtos() []*protocol.Proto {
+	if m != nil {
+		return m.Protos
+	}
+	return nil
+}
+
+type SendMsgReq struct {
+	Mid                  int64           `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Key                  string          `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string          `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	Proto                *protocol.Proto `protobuf:"bytes,4,opt,name=proto,proto3" json:"proto,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *SendMsgReq) Reset()         { *m = SendMsgReq{} }
+func (m *SendMsgReq) String() string { return proto.CompactTextString(m) }
+func (*SendMsgReq) ProtoMessage()    {}
+func (*SendMsgReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{19}
+}
+
+func (m *SendMsgReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendMsgReq.Unmarshal(m, b)
+}
+func (m *SendMsgReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendMsgReq.Marshal(b, m, deterministic)
+}
+func (m *SendMsgReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendMsgReq.Merge(m, src)
+}
+func (m *SendMsgReq) XXX_Size() int {
+	return xxx_messageInfo_SendMsgReq.Size(m)
+}
+func (m *SendMsgReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendMsgReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendMsgReq proto.InternalMessageInfo
+
+func (m *SendMsgReq) GetMid() int64 {
+	if m != nil {
+		return m.Mid
+	}
+	return 0
+}
+
+func (m *SendMsgReq) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SendMsgReq) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *SendMsgReq) GetProto() *protocol.Proto {
+	if m != nil {
+		return m.Proto
+	}
+	return nil
+}
+
+type SendMsgReply struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status               int32    `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendMsgReply) Reset()         { *m = SendMsgReply{} }
+func (m *SendMsgReply) String() string { return proto.CompactTextString(m) }
+func (*SendMsgReply) ProtoMessage()    {}
+func (*SendMsgReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{20}
+}
+
+func (m *SendMsgReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendMsgReply.Unmarshal(m, b)
+}
+func (m *SendMsgReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendMsgReply.Marshal(b, m, deterministic)
+}
+func (m *SendMsgReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendMsgReply.Merge(m, src)
+}
+func (m *SendMsgReply) XXX_Size() int {
+	return xxx_messageInfo_SendMsgReply.Size(m)
+}
+func (m *SendMsgReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendMsgReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendMsgReply proto.InternalMessageInfo
+
+func (m *SendMsgReply) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SendMsgReply) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+type PresenceReq struct {
+	Mids                 []int64  `protobuf:"varint,1,rep,packed,name=mids,proto3" json:"mids,omitempty"`
+	Detail               bool     `protobuf:"varint,2,opt,name=detail,proto3" json:"detail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PresenceReq) Reset()         { *m = PresenceReq{} }
+func (m *PresenceReq) String() string { return proto.CompactTextString(m) }
+func (*PresenceReq) ProtoMessage()    {}
+func (*PresenceReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{21}
+}
+
+func (m *PresenceReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PresenceReq.Unmarshal(m, b)
+}
+func (m *PresenceReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PresenceReq.Marshal(b, m, deterministic)
+}
+func (m *PresenceReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PresenceReq.Merge(m, src)
+}
+func (m *PresenceReq) XXX_Size() int {
+	return xxx_messageInfo_PresenceReq.Size(m)
+}
+func (m *PresenceReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_PresenceReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PresenceReq proto.InternalMessageInfo
+
+func (m *PresenceReq) GetMids() []int64 {
+	if m != nil {
+		return m.Mids
+	}
+	return nil
+}
+
+func (m *PresenceReq) GetDetail() bool {
+	if m != nil {
+		return m.Detail
+	}
+	return false
+}
+
+type KeyInfo struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string   `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	Platform             string   `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	Ip                   string   `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	Connected            int64    `protobuf:"varint,5,opt,name=connected,proto3" json:"connected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyInfo) Reset()         { *m = KeyInfo{} }
+func (m *KeyInfo) String() string { return proto.CompactTextString(m) }
+func (*KeyInfo) ProtoMessage()    {}
+func (*KeyInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{22}
+}
+
+func (m *KeyInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyInfo.Unmarshal(m, b)
+}
+func (m *KeyInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyInfo.Marshal(b, m, deterministic)
+}
+func (m *KeyInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyInfo.Merge(m, src)
+}
+func (m *KeyInfo) XXX_Size() int {
+	return xxx_messageInfo_KeyInfo.Size(m)
+}
+func (m *KeyInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyInfo proto.InternalMessageInfo
+
+func (m *KeyInfo) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KeyInfo) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *KeyInfo) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *KeyInfo) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *KeyInfo) GetConnected() int64 {
+	if m != nil {
+		return m.Connected
+	}
+	return 0
+}
+
+type Presence struct {
+	Mid                  int64      `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Online               bool       `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	Count                int32      `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Servers              []string   `protobuf:"bytes,4,rep,name=servers,proto3" json:"servers,omitempty"`
+	Keys                 []*KeyInfo `protobuf:"bytes,5,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Presence) Reset()         { *m = Presence{} }
+func (m *Presence) String() string { return proto.CompactTextString(m) }
+func (*Presence) ProtoMessage()    {}
+func (*Presence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{23}
+}
+
+func (m *Presence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Presence.Unmarshal(m, b)
+}
+func (m *Presence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Presence.Marshal(b, m, deterministic)
+}
+func (m *Presence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Presence.Merge(m, src)
+}
+func (m *Presence) XXX_Size() int {
+	return xxx_messageInfo_Presence.Size(m)
+}
+func (m *Presence) XXX_DiscardUnknown() {
+	xxx_messageInfo_Presence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Presence proto.InternalMessageInfo
+
+func (m *Presence) GetMid() int64 {
+	if m != nil {
+		return m.Mid
+	}
+	return 0
+}
+
+func (m *Presence) GetOnline() bool {
+	if m != nil {
+		return m.Online
+	}
+	return false
+}
+
+func (m *Presence) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Presence) GetServers() []string {
+	if m != nil {
+		return m.Servers
+	}
+	return nil
+}
+
+func (m *Presence) GetKeys() []*KeyInfo {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type PresenceReply struct {
+	Presences            []*Presence `protobuf:"bytes,1,rep,name=presences,proto3" json:"presences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *PresenceReply) Reset()         { *m = PresenceReply{} }
+func (m *PresenceReply) String() string { return proto.CompactTextString(m) }
+func (*PresenceReply) ProtoMessage()    {}
+func (*PresenceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{24}
+}
+
+func (m *PresenceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PresenceReply.Unmarshal(m, b)
+}
+func (m *PresenceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PresenceReply.Marshal(b, m, deterministic)
+}
+func (m *PresenceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PresenceReply.Merge(m, src)
+}
+func (m *PresenceReply) XXX_Size() int {
+	return xxx_messageInfo_PresenceReply.Size(m)
+}
+func (m *PresenceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PresenceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PresenceReply proto.InternalMessageInfo
+
+func (m *PresenceReply) GetPresences() []*Presence {
+	if m != nil {
+		return m.Presences
+	}
+	return nil
+}
+
+type NodesReq struct {
+	Platform             string   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	ClientIP             string   `protobuf:"bytes,2,opt,name=clientIP,proto3" json:"clientIP,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodesReq) Reset()         { *m = NodesReq{} }
+func (m *NodesReq) String() string { return proto.CompactTextString(m) }
+func (*NodesReq) ProtoMessage()    {}
+func (*NodesReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{25}
+}
+
+func (m *NodesReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodesReq.Unmarshal(m, b)
+}
+func (m *NodesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodesReq.Marshal(b, m, deterministic)
+}
+func (m *NodesReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodesReq.Merge(m, src)
+}
+func (m *NodesReq) XXX_Size() int {
+	return xxx_messageInfo_NodesReq.Size(m)
+}
+func (m *NodesReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodesReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodesReq proto.InternalMessageInfo
+
+func (m *NodesReq) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *NodesReq) GetClientIP() string {
+	if m != nil {
+		return m.ClientIP
+	}
+	return ""
+}
+
+type NodesReply struct {
+	Domain               string   `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	TcpPort              int32    `protobuf:"varint,2,opt,name=tcp_port,json=tcpPort,proto3" json:"tcp_port,omitempty"`
+	WsPort               int32    `protobuf:"varint,3,opt,name=ws_port,json=wsPort,proto3" json:"ws_port,omitempty"`
+	WssPort              int32    `protobuf:"varint,4,opt,name=wss_port,json=wssPort,proto3" json:"wss_port,omitempty"`
+	Heartbeat            int32    `protobuf:"varint,5,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	Nodes                []string `protobuf:"bytes,6,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Backoff              *Backoff `protobuf:"bytes,7,opt,name=backoff,proto3" json:"backoff,omitempty"`
+	HeartbeatMax         int32    `protobuf:"varint,8,opt,name=heartbeat_max,json=heartbeatMax,proto3" json:"heartbeat_max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodesReply) Reset()         { *m = NodesReply{} }
+func (m *NodesReply) String() string { return proto.CompactTextString(m) }
+func (*NodesReply) ProtoMessage()    {}
+func (*NodesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{26}
+}
+
+func (m *NodesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodesReply.Unmarshal(m, b)
+}
+func (m *NodesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodesReply.Marshal(b, m, deterministic)
+}
+func (m *NodesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodesReply.Merge(m, src)
+}
+func (m *NodesReply) XXX_Size() int {
+	return xxx_messageInfo_NodesReply.Size(m)
+}
+func (m *NodesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodesReply proto.InternalMessageInfo
+
+func (m *NodesReply) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *NodesReply) GetTcpPort() int32 {
+	if m != nil {
+		return m.TcpPort
+	}
+	return 0
+}
+
+func (m *NodesReply) GetWsPort() int32 {
+	if m != nil {
+		return m.WsPort
+	}
+	return 0
+}
+
+func (m *NodesReply) GetWssPort() int32 {
+	if m != nil {
+		return m.WssPort
+	}
+	return 0
+}
+
+func (m *NodesReply) GetHeartbeat() int32 {
+	if m != nil {
+		return m.Heartbeat
+	}
+	return 0
+}
+
+func (m *NodesReply) GetNodes() []string {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *NodesReply) GetBackoff() *Backoff {
+	if m != nil {
+		return m.Backoff
+	}
+	return nil
+}
+
+func (m *NodesReply) GetHeartbeatMax() int32 {
 	if m != nil {
 		return m.HeartbeatMax
 	}
 	return 0
 }
 
-type Backoff struct {
-	MaxDelay             int32    `protobuf:"varint,1,opt,name=max_delay,json=maxDelay,proto3" json:"max_delay,omitempty"`
-	BaseDelay            int32    `protobuf:"varint,2,opt,name=base_delay,json=baseDelay,proto3" json:"base_delay,omitempty"`
-	Factor               float32  `protobuf:"fixed32,3,opt,name=factor,proto3" json:"factor,omitempty"`
-	Jitter               float32  `protobuf:"fixed32,4,opt,name=jitter,proto3" json:"jitter,omitempty"`
+type Backoff struct {
+	MaxDelay             int32    `protobuf:"varint,1,opt,name=max_delay,json=maxDelay,proto3" json:"max_delay,omitempty"`
+	BaseDelay            int32    `protobuf:"varint,2,opt,name=base_delay,json=baseDelay,proto3" json:"base_delay,omitempty"`
+	Factor               float32  `protobuf:"fixed32,3,opt,name=factor,proto3" json:"factor,omitempty"`
+	Jitter               float32  `protobuf:"fixed32,4,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Backoff) Reset()         { *m = Backoff{} }
+func (m *Backoff) String() string { return proto.CompactTextString(m) }
+func (*Backoff) ProtoMessage()    {}
+func (*Backoff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{27}
+}
+
+func (m *Backoff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Backoff.Unmarshal(m, b)
+}
+func (m *Backoff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Backoff.Marshal(b, m, deterministic)
+}
+func (m *Backoff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Backoff.Merge(m, src)
+}
+func (m *Backoff) XXX_Size() int {
+	return xxx_messageInfo_Backoff.Size(m)
+}
+func (m *Backoff) XXX_DiscardUnknown() {
+	xxx_messageInfo_Backoff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Backoff proto.InternalMessageInfo
+
+func (m *Backoff) GetMaxDelay() int32 {
+	if m != nil {
+		return m.MaxDelay
+	}
+	return 0
+}
+
+func (m *Backoff) GetBaseDelay() int32 {
+	if m != nil {
+		return m.BaseDelay
+	}
+	return 0
+}
+
+func (m *Backoff) GetFactor() float32 {
+	if m != nil {
+		return m.Factor
+	}
+	return 0
+}
+
+func (m *Backoff) GetJitter() float32 {
+	if m != nil {
+		return m.Jitter
+	}
+	return 0
+}
+
+type KickKeysReq struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Reason               int32    `protobuf:"varint,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickKeysReq) Reset()         { *m = KickKeysReq{} }
+func (m *KickKeysReq) String() string { return proto.CompactTextString(m) }
+func (*KickKeysReq) ProtoMessage()    {}
+func (*KickKeysReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{28}
+}
+
+func (m *KickKeysReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickKeysReq.Unmarshal(m, b)
+}
+func (m *KickKeysReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickKeysReq.Marshal(b, m, deterministic)
+}
+func (m *KickKeysReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickKeysReq.Merge(m, src)
+}
+func (m *KickKeysReq) XXX_Size() int {
+	return xxx_messageInfo_KickKeysReq.Size(m)
+}
+func (m *KickKeysReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickKeysReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickKeysReq proto.InternalMessageInfo
+
+func (m *KickKeysReq) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *KickKeysReq) GetReason() int32 {
+	if m != nil {
+		return m.Reason
+	}
+	return 0
+}
+
+type KickMidsReq struct {
+	Mids                 []int64  `protobuf:"varint,1,rep,packed,name=mids,proto3" json:"mids,omitempty"`
+	Reason               int32    `protobuf:"varint,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickMidsReq) Reset()         { *m = KickMidsReq{} }
+func (m *KickMidsReq) String() string { return proto.CompactTextString(m) }
+func (*KickMidsReq) ProtoMessage()    {}
+func (*KickMidsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{29}
+}
+
+func (m *KickMidsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickMidsReq.Unmarshal(m, b)
+}
+func (m *KickMidsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickMidsReq.Marshal(b, m, deterministic)
+}
+func (m *KickMidsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickMidsReq.Merge(m, src)
+}
+func (m *KickMidsReq) XXX_Size() int {
+	return xxx_messageInfo_KickMidsReq.Size(m)
+}
+func (m *KickMidsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickMidsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickMidsReq proto.InternalMessageInfo
+
+func (m *KickMidsReq) GetMids() []int64 {
+	if m != nil {
+		return m.Mids
+	}
+	return nil
+}
+
+func (m *KickMidsReq) GetReason() int32 {
+	if m != nil {
+		return m.Reason
+	}
+	return 0
+}
+
+type KickReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickReply) Reset()         { *m = KickReply{} }
+func (m *KickReply) String() string { return proto.CompactTextString(m) }
+func (*KickReply) ProtoMessage()    {}
+func (*KickReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{30}
+}
+
+func (m *KickReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickReply.Unmarshal(m, b)
+}
+func (m *KickReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickReply.Marshal(b, m, deterministic)
+}
+func (m *KickReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickReply.Merge(m, src)
+}
+func (m *KickReply) XXX_Size() int {
+	return xxx_messageInfo_KickReply.Size(m)
+}
+func (m *KickReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickReply proto.InternalMessageInfo
+
+type AuthorizeRoomReq struct {
+	Mid                  int64    `protobuf:"varint,1,opt,name=mid,proto3" json:"mid,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Server               string   `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
+	Room                 string   `protobuf:"bytes,4,opt,name=room,proto3" json:"room,omitempty"`
+	Ticket               string   `protobuf:"bytes,5,opt,name=ticket,proto3" json:"ticket,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Backoff) Reset()         { *m = Backoff{} }
-func (m *Backoff) String() string { return proto.CompactTextString(m) }
-func (*Backoff) ProtoMessage()    {}
-func (*Backoff) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dfb3aef05fe3328, []int{13}
+func (m *AuthorizeRoomReq) Reset()         { *m = AuthorizeRoomReq{} }
+func (m *AuthorizeRoomReq) String() string { return proto.CompactTextString(m) }
+func (*AuthorizeRoomReq) ProtoMessage()    {}
+func (*AuthorizeRoomReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{31}
 }
 
-func (m *Backoff) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Backoff.Unmarshal(m, b)
+func (m *AuthorizeRoomReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthorizeRoomReq.Unmarshal(m, b)
 }
-func (m *Backoff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Backoff.Marshal(b, m, deterministic)
+func (m *AuthorizeRoomReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthorizeRoomReq.Marshal(b, m, deterministic)
 }
-func (m *Backoff) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Backoff.Merge(m, src)
+func (m *AuthorizeRoomReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizeRoomReq.Merge(m, src)
 }
-func (m *Backoff) XXX_Size() int {
-	return xxx_messageInfo_Backoff.Size(m)
+func (m *AuthorizeRoomReq) XXX_Size() int {
+	return xxx_messageInfo_AuthorizeRoomReq.Size(m)
 }
-func (m *Backoff) XXX_DiscardUnknown() {
-	xxx_messageInfo_Backoff.DiscardUnknown(m)
+func (m *AuthorizeRoomReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizeRoomReq.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Backoff proto.InternalMessageInfo
+var xxx_messageInfo_AuthorizeRoomReq proto.InternalMessageInfo
 
-func (m *Backoff) GetMaxDelay() int32 {
+func (m *AuthorizeRoomReq) GetMid() int64 {
 	if m != nil {
-		return m.MaxDelay
+		return m.Mid
 	}
 	return 0
 }
 
-func (m *Backoff) GetBaseDelay() int32 {
+func (m *AuthorizeRoomReq) GetKey() string {
 	if m != nil {
-		return m.BaseDelay
+		return m.Key
 	}
-	return 0
+	return ""
 }
 
-func (m *Backoff) GetFactor() float32 {
+func (m *AuthorizeRoomReq) GetServer() string {
 	if m != nil {
-		return m.Factor
+		return m.Server
 	}
-	return 0
+	return ""
 }
 
-func (m *Backoff) GetJitter() float32 {
+func (m *AuthorizeRoomReq) GetRoom() string {
 	if m != nil {
-		return m.Jitter
+		return m.Room
+	}
+	return ""
+}
+
+func (m *AuthorizeRoomReq) GetTicket() string {
+	if m != nil {
+		return m.Ticket
+	}
+	return ""
+}
+
+type AuthorizeRoomReply struct {
+	Allow                bool     `protobuf:"varint,1,opt,name=allow,proto3" json:"allow,omitempty"`
+	Code                 int32    `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthorizeRoomReply) Reset()         { *m = AuthorizeRoomReply{} }
+func (m *AuthorizeRoomReply) String() string { return proto.CompactTextString(m) }
+func (*AuthorizeRoomReply) ProtoMessage()    {}
+func (*AuthorizeRoomReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dfb3aef05fe3328, []int{32}
+}
+
+func (m *AuthorizeRoomReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthorizeRoomReply.Unmarshal(m, b)
+}
+func (m *AuthorizeRoomReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthorizeRoomReply.Marshal(b, m, deterministic)
+}
+func (m *AuthorizeRoomReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizeRoomReply.Merge(m, src)
+}
+func (m *AuthorizeRoomReply) XXX_Size() int {
+	return xxx_messageInfo_AuthorizeRoomReply.Size(m)
+}
+func (m *AuthorizeRoomReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizeRoomReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizeRoomReply proto.InternalMessageInfo
+
+func (m *AuthorizeRoomReply) GetAllow() bool {
+	if m != nil {
+		return m.Allow
+	}
+	return false
+}
+
+func (m *AuthorizeRoomReply) GetCode() int32 {
+	if m != nil {
+		return m.Code
 	}
 	return 0
 }
@@ -818,6 +1882,8 @@ func (m *Backoff) GetJitter() float32 {
 func init() {
 	proto.RegisterEnum("goim.logic.PushMsg_Type", PushMsg_Type_name, PushMsg_Type_value)
 	proto.RegisterType((*PushMsg)(nil), "goim.logic.PushMsg")
+	proto.RegisterType((*UpstreamMsg)(nil), "goim.logic.UpstreamMsg")
+	proto.RegisterType((*PresenceEvent)(nil), "goim.logic.PresenceEvent")
 	proto.RegisterType((*ConnectReq)(nil), "goim.logic.ConnectReq")
 	proto.RegisterType((*ConnectReply)(nil), "goim.logic.ConnectReply")
 	proto.RegisterType((*DisconnectReq)(nil), "goim.logic.DisconnectReq")
@@ -830,71 +1896,125 @@ func init() {
 	proto.RegisterMapType((map[string]int32)(nil), "goim.logic.OnlineReply.AllRoomCountEntry")
 	proto.RegisterType((*ReceiveReq)(nil), "goim.logic.ReceiveReq")
 	proto.RegisterType((*ReceiveReply)(nil), "goim.logic.ReceiveReply")
+	proto.RegisterType((*UnackedReq)(nil), "goim.logic.UnackedReq")
+	proto.RegisterType((*UnackedReply)(nil), "goim.logic.UnackedReply")
+	proto.RegisterType((*SyncReq)(nil), "goim.logic.SyncReq")
+	proto.RegisterType((*SyncReply)(nil), "goim.logic.SyncReply")
+	proto.RegisterType((*RoomHistoryReq)(nil), "goim.logic.RoomHistoryReq")
+	proto.RegisterType((*RoomHistoryReply)(nil), "goim.logic.RoomHistoryReply")
+	proto.RegisterType((*SendMsgReq)(nil), "goim.logic.SendMsgReq")
+	proto.RegisterType((*SendMsgReply)(nil), "goim.logic.SendMsgReply")
+	proto.RegisterType((*PresenceReq)(nil), "goim.logic.PresenceReq")
+	proto.RegisterType((*KeyInfo)(nil), "goim.logic.KeyInfo")
+	proto.RegisterType((*Presence)(nil), "goim.logic.Presence")
+	proto.RegisterType((*PresenceReply)(nil), "goim.logic.PresenceReply")
 	proto.RegisterType((*NodesReq)(nil), "goim.logic.NodesReq")
 	proto.RegisterType((*NodesReply)(nil), "goim.logic.NodesReply")
 	proto.RegisterType((*Backoff)(nil), "goim.logic.Backoff")
+	proto.RegisterType((*KickKeysReq)(nil), "goim.logic.KickKeysReq")
+	proto.RegisterType((*KickMidsReq)(nil), "goim.logic.KickMidsReq")
+	proto.RegisterType((*KickReply)(nil), "goim.logic.KickReply")
+	proto.RegisterType((*AuthorizeRoomReq)(nil), "goim.logic.AuthorizeRoomReq")
+	proto.RegisterType((*AuthorizeRoomReply)(nil), "goim.logic.AuthorizeRoomReply")
 }
 
 func init() { proto.RegisterFile("logic/logic.proto", fileDescriptor_2dfb3aef05fe3328) }
 
 var fileDescriptor_2dfb3aef05fe3328 = []byte{
-	// 893 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x4f, 0x6f, 0xe3, 0x44,
-	0x14, 0xc7, 0x71, 0x9c, 0xc4, 0x2f, 0x69, 0xc9, 0x0e, 0x25, 0xeb, 0x7a, 0x41, 0x8a, 0xbc, 0x1c,
-	0x52, 0x60, 0x13, 0x29, 0x68, 0x25, 0xc4, 0x82, 0x50, 0xd3, 0x20, 0xed, 0x2e, 0x84, 0x46, 0xb3,
-	0xe5, 0xc2, 0xa5, 0x9a, 0x38, 0xd3, 0xd4, 0xc4, 0xf6, 0x18, 0x7b, 0xd2, 0xd4, 0x37, 0xc4, 0xf7,
-	0xe0, 0xc8, 0x77, 0xe3, 0x3b, 0x70, 0x41, 0xf3, 0x27, 0xb6, 0xa3, 0x4d, 0x57, 0x20, 0x2e, 0xd6,
-	0xfb, 0x37, 0xbf, 0xf9, 0xbd, 0xf7, 0xe6, 0x3d, 0xc3, 0xa3, 0x90, 0xad, 0x02, 0x7f, 0x24, 0xbf,
-	0xc3, 0x24, 0x65, 0x9c, 0x21, 0x58, 0xb1, 0x20, 0x1a, 0x4a, 0x8b, 0xfb, 0x7c, 0x15, 0xf0, 0xdb,
-	0xcd, 0x62, 0xe8, 0xb3, 0x68, 0x74, 0x45, 0xd3, 0x34, 0x7f, 0x36, 0x23, 0x6c, 0x24, 0x02, 0x46,
-	0x24, 0x09, 0x46, 0xf2, 0x80, 0xcf, 0xc2, 0x42, 0x50, 0x10, 0xde, 0x5f, 0x06, 0x34, 0xe7, 0x9b,
-	0xec, 0x76, 0x96, 0xad, 0xd0, 0xe7, 0x50, 0xe7, 0x79, 0x42, 0x1d, 0xa3, 0x6f, 0x0c, 0x8e, 0xc7,
-	0xce, 0xb0, 0x44, 0x1f, 0xea, 0x90, 0xe1, 0x55, 0x9e, 0x50, 0x2c, 0xa3, 0xd0, 0x47, 0x60, 0xb3,
-	0x84, 0xa6, 0x84, 0x07, 0x2c, 0x76, 0x6a, 0x7d, 0x63, 0x60, 0xe1, 0xd2, 0x80, 0x4e, 0xc0, 0xca,
-	0x12, 0x4a, 0x97, 0x8e, 0x29, 0x3d, 0x4a, 0x41, 0x3d, 0x68, 0x64, 0x34, 0xbd, 0xa3, 0xa9, 0x53,
-	0xef, 0x1b, 0x03, 0x1b, 0x6b, 0x0d, 0x21, 0xa8, 0xa7, 0x8c, 0x45, 0x8e, 0x25, 0xad, 0x52, 0x16,
-	0xb6, 0x35, 0xcd, 0x33, 0xa7, 0xd1, 0x37, 0x85, 0x4d, 0xc8, 0xa8, 0x0b, 0x66, 0x94, 0xad, 0x9c,
-	0x66, 0xdf, 0x18, 0x74, 0xb0, 0x10, 0xbd, 0x33, 0xa8, 0x0b, 0x4e, 0xa8, 0x05, 0xf5, 0xf9, 0x4f,
-	0x6f, 0x5e, 0x76, 0xdf, 0x13, 0x12, 0xbe, 0xbc, 0x9c, 0x75, 0x0d, 0x74, 0x04, 0xf6, 0x04, 0x5f,
-	0x9e, 0x4f, 0x2f, 0xce, 0xdf, 0x5c, 0x75, 0x6b, 0x1e, 0x06, 0xb8, 0x60, 0x71, 0x4c, 0x7d, 0x8e,
-	0xe9, 0xaf, 0x15, 0x2a, 0xc6, 0x1e, 0x95, 0x1e, 0x34, 0x7c, 0xc6, 0xd6, 0x01, 0x95, 0x39, 0xd9,
-	0x58, 0x6b, 0x22, 0x21, 0xce, 0xd6, 0x34, 0x96, 0x09, 0x75, 0xb0, 0x52, 0xbc, 0xdf, 0x0d, 0xe8,
-	0x14, 0xa0, 0x49, 0x98, 0x4b, 0x86, 0xc1, 0x52, 0x62, 0x9a, 0x58, 0x88, 0xc2, 0xb2, 0xa6, 0xb9,
-	0x46, 0x13, 0xa2, 0xb8, 0x42, 0x64, 0xf8, 0x6a, 0x2a, 0xb1, 0x6c, 0xac, 0x35, 0xe4, 0x40, 0x93,
-	0xf8, 0x3e, 0x4d, 0x78, 0xe6, 0xd4, 0xfb, 0xe6, 0xc0, 0xc2, 0x3b, 0x55, 0xd4, 0xfa, 0x96, 0x92,
-	0x94, 0x2f, 0x28, 0xe1, 0xb2, 0x48, 0x26, 0x2e, 0x0d, 0xde, 0xf7, 0x70, 0x34, 0x0d, 0x32, 0xbf,
-	0xcc, 0xed, 0x5f, 0x92, 0xd0, 0xf9, 0x9b, 0xd5, 0xfc, 0xbd, 0xa7, 0xf0, 0x7e, 0x15, 0x4c, 0xe7,
-	0x74, 0x4b, 0x32, 0x09, 0xd7, 0xc2, 0x42, 0xf4, 0x5e, 0x43, 0xe7, 0xe5, 0xee, 0xfa, 0xff, 0x7b,
-	0x61, 0x17, 0x8e, 0x2b, 0x58, 0x49, 0x98, 0x7b, 0x7f, 0x1a, 0x60, 0x5f, 0xc6, 0x61, 0x10, 0xd3,
-	0x77, 0x35, 0x6a, 0x02, 0xb6, 0xa8, 0xdb, 0x05, 0xdb, 0xc4, 0xdc, 0xa9, 0xf5, 0xcd, 0x41, 0x7b,
-	0xfc, 0x49, 0xf5, 0xc9, 0x16, 0x08, 0x43, 0xbc, 0x0b, 0xfb, 0x2e, 0xe6, 0x69, 0x8e, 0xcb, 0x63,
-	0xee, 0xd7, 0x70, 0xbc, 0xef, 0xdc, 0xf1, 0x36, 0x4a, 0xde, 0x27, 0x60, 0xdd, 0x91, 0x70, 0x43,
-	0xf5, 0x1b, 0x57, 0xca, 0x57, 0xb5, 0x2f, 0x0d, 0xef, 0x0f, 0x03, 0xda, 0xbb, 0x5b, 0x44, 0x9d,
-	0x66, 0xd0, 0x21, 0x61, 0x58, 0x00, 0x3a, 0x86, 0x24, 0x75, 0x76, 0x88, 0x54, 0x12, 0xe6, 0xc3,
-	0xf3, 0x4a, 0xac, 0x62, 0xb6, 0x77, 0xdc, 0xfd, 0x16, 0x1e, 0xbd, 0x15, 0xf2, 0x9f, 0xf8, 0xbd,
-	0x06, 0xc0, 0xd4, 0xa7, 0xc1, 0x1d, 0x3d, 0xdc, 0xa3, 0x4f, 0xc1, 0x92, 0x4b, 0x40, 0x9e, 0x6c,
-	0x8f, 0x4f, 0x14, 0xd1, 0x62, 0x41, 0xcc, 0x85, 0x80, 0x55, 0x88, 0x77, 0x0c, 0x9d, 0x02, 0x4b,
-	0xf4, 0x68, 0x02, 0xad, 0x1f, 0xd9, 0x92, 0x66, 0x02, 0xd9, 0x85, 0x56, 0x12, 0x12, 0x7e, 0xc3,
-	0xd2, 0x48, 0x13, 0x2b, 0x74, 0xe1, 0xf3, 0xc3, 0x80, 0xc6, 0xfc, 0xd5, 0x5c, 0x3f, 0x86, 0x42,
-	0xf7, 0xfe, 0x36, 0x00, 0x34, 0x88, 0x28, 0x5f, 0x0f, 0x1a, 0x4b, 0x16, 0x91, 0x20, 0xde, 0x35,
-	0x5a, 0x69, 0xe8, 0x14, 0x5a, 0xdc, 0x4f, 0xae, 0x13, 0x96, 0x72, 0x9d, 0x63, 0x93, 0xfb, 0xc9,
-	0x9c, 0xa5, 0x1c, 0x3d, 0x86, 0xe6, 0x36, 0x53, 0x1e, 0xb5, 0x67, 0x1a, 0xdb, 0x4c, 0x3a, 0x4e,
-	0xa1, 0xb5, 0xcd, 0xb4, 0xa7, 0xae, 0xce, 0x6c, 0x33, 0xe5, 0x7a, 0x6b, 0x96, 0xac, 0xca, 0x2c,
-	0x89, 0x6a, 0xc6, 0x82, 0x92, 0x5e, 0x3b, 0x4a, 0x41, 0xcf, 0xa0, 0xb9, 0x20, 0xfe, 0x9a, 0xdd,
-	0xdc, 0xc8, 0xdd, 0xd3, 0x1e, 0x7f, 0x50, 0x6d, 0xea, 0x44, 0xb9, 0xf0, 0x2e, 0x06, 0x3d, 0x85,
-	0xa3, 0x02, 0xf1, 0x3a, 0x22, 0xf7, 0x4e, 0x4b, 0x5e, 0xd3, 0x29, 0x8c, 0x33, 0x72, 0xef, 0x6d,
-	0xa0, 0xa9, 0x0f, 0xa2, 0x27, 0x60, 0x47, 0xe4, 0xfe, 0x7a, 0x49, 0x43, 0xa2, 0x5a, 0x6b, 0xe1,
-	0x56, 0x44, 0xee, 0xa7, 0x42, 0x47, 0x1f, 0x03, 0x2c, 0x48, 0x46, 0xb5, 0x57, 0x2f, 0x5a, 0x61,
-	0x51, 0xee, 0x1e, 0x34, 0x6e, 0x88, 0xcf, 0x99, 0x1a, 0xab, 0x1a, 0xd6, 0x9a, 0xb0, 0xff, 0x12,
-	0x70, 0xae, 0x57, 0x6d, 0x0d, 0x6b, 0x6d, 0xfc, 0x9b, 0x09, 0xd6, 0x0f, 0x82, 0x36, 0x7a, 0x01,
-	0x4d, 0xbd, 0xba, 0x50, 0xaf, 0x9a, 0x4e, 0xb9, 0x24, 0x5d, 0xe7, 0xa0, 0x5d, 0x34, 0x6b, 0x0a,
-	0x50, 0xae, 0x09, 0x74, 0x5a, 0x8d, 0xdb, 0xdb, 0x45, 0xee, 0x93, 0x87, 0x5c, 0x02, 0xe5, 0x1c,
-	0xec, 0x62, 0xf6, 0xd1, 0xde, 0x65, 0xd5, 0xf5, 0xe2, 0xba, 0x0f, 0x78, 0x04, 0xc4, 0x37, 0xd0,
-	0xc6, 0x34, 0xa6, 0x5b, 0x35, 0x59, 0xe8, 0xc3, 0x83, 0x2b, 0xc0, 0x7d, 0xfc, 0xc0, 0x10, 0x8a,
-	0x22, 0xe8, 0x77, 0xbd, 0x5f, 0x84, 0x72, 0x70, 0xf6, 0x8b, 0x50, 0x1d, 0x02, 0xf4, 0x1c, 0x2c,
-	0xf9, 0x7e, 0xd1, 0x49, 0x35, 0x64, 0x37, 0x17, 0x6e, 0xef, 0x80, 0x35, 0x09, 0xf3, 0xc9, 0x67,
-	0x3f, 0x9f, 0xbd, 0xfb, 0x67, 0x2d, 0x4f, 0xbc, 0x90, 0xdf, 0x45, 0x43, 0xce, 0xdf, 0x17, 0xff,
-	0x04, 0x00, 0x00, 0xff, 0xff, 0x6f, 0x34, 0x54, 0x90, 0xff, 0x07, 0x00, 0x00,
+	// 1477 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x5b, 0x6f, 0xdb, 0xc6,
+	0x12, 0x3e, 0x94, 0x44, 0x5d, 0x46, 0xb2, 0x8f, 0xb2, 0xc7, 0x71, 0x68, 0x26, 0xe7, 0xc0, 0x60,
+	0x0e, 0x50, 0xa7, 0x4d, 0xe5, 0xc2, 0x41, 0x9a, 0x26, 0x69, 0xd3, 0xfa, 0x12, 0x34, 0xae, 0xab,
+	0xda, 0xa0, 0x13, 0x14, 0xe8, 0x4b, 0x40, 0x53, 0x6b, 0x9b, 0x15, 0xc5, 0x65, 0xb8, 0xeb, 0x0b,
+	0x03, 0xf4, 0xa5, 0xef, 0x45, 0xff, 0x40, 0xfb, 0xd8, 0x5f, 0x59, 0xa0, 0x28, 0x66, 0x77, 0x79,
+	0x93, 0x65, 0x37, 0x81, 0x5f, 0x88, 0x9d, 0x99, 0x9d, 0x9d, 0x6f, 0x66, 0x77, 0x2e, 0x20, 0xdc,
+	0x08, 0xd9, 0x51, 0xe0, 0xaf, 0xca, 0xef, 0x20, 0x4e, 0x98, 0x60, 0x04, 0x8e, 0x58, 0x30, 0x19,
+	0x48, 0x8e, 0xfd, 0xf0, 0x28, 0x10, 0xc7, 0x27, 0x07, 0x03, 0x9f, 0x4d, 0x56, 0x8f, 0xd2, 0xf4,
+	0x93, 0x47, 0x6b, 0x8f, 0x56, 0x27, 0x29, 0x6e, 0x58, 0xf5, 0xe2, 0x60, 0x55, 0x2a, 0xf8, 0x2c,
+	0xcc, 0x17, 0xea, 0x08, 0xe7, 0xb7, 0x1a, 0xb4, 0xf6, 0x4e, 0xf8, 0xf1, 0x90, 0x1f, 0x91, 0xfb,
+	0xd0, 0x10, 0x69, 0x4c, 0x2d, 0x63, 0xd9, 0x58, 0x99, 0x5f, 0xb3, 0x06, 0xc5, 0xe9, 0x03, 0xbd,
+	0x65, 0xf0, 0x32, 0x8d, 0xa9, 0x2b, 0x77, 0x91, 0x3b, 0xd0, 0x61, 0x31, 0x4d, 0x3c, 0x11, 0xb0,
+	0xc8, 0xaa, 0x2d, 0x1b, 0x2b, 0xa6, 0x5b, 0x30, 0xc8, 0x02, 0x98, 0x3c, 0xa6, 0x74, 0x64, 0xd5,
+	0xa5, 0x44, 0x11, 0x64, 0x11, 0x9a, 0x9c, 0x26, 0xa7, 0x34, 0xb1, 0x1a, 0xcb, 0xc6, 0x4a, 0xc7,
+	0xd5, 0x14, 0x21, 0xd0, 0x48, 0x18, 0x9b, 0x58, 0xa6, 0xe4, 0xca, 0x35, 0xf2, 0xc6, 0x34, 0xe5,
+	0x56, 0x73, 0xb9, 0x8e, 0x3c, 0x5c, 0x93, 0x3e, 0xd4, 0x27, 0xfc, 0xc8, 0x6a, 0x2d, 0x1b, 0x2b,
+	0x3d, 0x17, 0x97, 0xc8, 0xe1, 0xf4, 0x8d, 0xd5, 0x96, 0x56, 0x70, 0x89, 0x36, 0x12, 0xea, 0x71,
+	0x16, 0x59, 0x1d, 0xc9, 0xd4, 0x94, 0xf3, 0x00, 0x1a, 0x88, 0x9e, 0xb4, 0xa1, 0xb1, 0xf7, 0x6a,
+	0xff, 0x45, 0xff, 0x5f, 0xb8, 0x72, 0x77, 0x77, 0x87, 0x7d, 0x83, 0xcc, 0x41, 0x67, 0xc3, 0xdd,
+	0x5d, 0xdf, 0xda, 0x5c, 0xdf, 0x7f, 0xd9, 0xaf, 0xa1, 0x60, 0x67, 0x7b, 0x73, 0xa7, 0x5f, 0x77,
+	0x7e, 0x35, 0xa0, 0xfb, 0x2a, 0xe6, 0x22, 0xa1, 0xde, 0x64, 0xa8, 0xcc, 0x4d, 0x82, 0x91, 0x8c,
+	0x50, 0xdd, 0xc5, 0x25, 0x72, 0xc6, 0x34, 0x95, 0x01, 0xe8, 0xb8, 0xb8, 0x2c, 0x39, 0x59, 0xaf,
+	0x38, 0x39, 0x0f, 0x35, 0x16, 0x4b, 0xc7, 0x4d, 0xb7, 0xc6, 0xe2, 0x0c, 0xba, 0x59, 0x40, 0x27,
+	0xd0, 0x38, 0x60, 0xa3, 0xd4, 0x6a, 0x4a, 0xff, 0xe4, 0x1a, 0xb5, 0x04, 0x97, 0x1e, 0xd7, 0xdd,
+	0x9a, 0xe0, 0x0e, 0x87, 0xb9, 0xbd, 0x84, 0x72, 0x1a, 0xf9, 0xf4, 0xf9, 0x29, 0x8d, 0xc4, 0x0c,
+	0x48, 0x8b, 0xd0, 0x64, 0x51, 0x18, 0x44, 0x54, 0xa2, 0x6a, 0xbb, 0x9a, 0xca, 0xa0, 0xd6, 0x67,
+	0x41, 0x6d, 0x4c, 0x43, 0x15, 0xdc, 0x32, 0x73, 0xa3, 0x07, 0x00, 0x9b, 0x2c, 0x8a, 0xa8, 0x2f,
+	0x5c, 0x15, 0x61, 0xad, 0x65, 0x54, 0xb4, 0x16, 0xa1, 0xe9, 0x33, 0x36, 0x0e, 0xa8, 0x8e, 0x86,
+	0xa6, 0xf0, 0x2d, 0x08, 0x36, 0xa6, 0x91, 0xb4, 0xdc, 0x73, 0x15, 0x81, 0x36, 0x82, 0x58, 0xdb,
+	0xad, 0x05, 0xb1, 0xf3, 0xb3, 0x01, 0xbd, 0xdc, 0x48, 0x1c, 0xa6, 0xef, 0x1a, 0x6b, 0x7c, 0x2c,
+	0xdb, 0x5b, 0x59, 0xac, 0x15, 0x45, 0x2c, 0x68, 0x79, 0xbe, 0x4f, 0x63, 0xc1, 0xad, 0xc6, 0x72,
+	0x7d, 0xc5, 0x74, 0x33, 0x12, 0x9f, 0xed, 0x31, 0xf5, 0x12, 0x71, 0x40, 0x3d, 0xa1, 0x3d, 0x2c,
+	0x18, 0xce, 0x0e, 0xcc, 0x6d, 0x05, 0xdc, 0x2f, 0x7c, 0xbd, 0xc6, 0x85, 0x3b, 0x77, 0xe1, 0xdf,
+	0xe5, 0xc3, 0xb4, 0x4f, 0xc7, 0x1e, 0x97, 0xc7, 0xb5, 0x5d, 0x5c, 0x3a, 0xdf, 0x40, 0xef, 0x45,
+	0x66, 0xfe, 0xba, 0x06, 0xfb, 0x30, 0x5f, 0x3a, 0x2b, 0x0e, 0x53, 0xe7, 0x0f, 0x03, 0x3a, 0xbb,
+	0xf2, 0xf6, 0xaf, 0xba, 0xb8, 0x0d, 0xe8, 0x60, 0xdc, 0x36, 0xd9, 0x49, 0x24, 0xac, 0xda, 0x72,
+	0x7d, 0xa5, 0xbb, 0xf6, 0xff, 0x72, 0xf6, 0xe7, 0x27, 0x0c, 0xdc, 0x6c, 0xdb, 0xf3, 0x48, 0x24,
+	0xa9, 0x5b, 0xa8, 0xd9, 0x9f, 0xc3, 0x7c, 0x55, 0x98, 0xe1, 0x36, 0x0a, 0xdc, 0x0b, 0x60, 0x9e,
+	0x7a, 0xe1, 0x09, 0xd5, 0xe5, 0x42, 0x11, 0x4f, 0x6a, 0x9f, 0x19, 0xce, 0xef, 0x06, 0x74, 0x33,
+	0x2b, 0x18, 0xa7, 0x21, 0xf4, 0xbc, 0x30, 0xcc, 0x0f, 0xb4, 0x0c, 0x09, 0xea, 0xde, 0x2c, 0x50,
+	0x71, 0x98, 0x0e, 0xd6, 0xc3, 0xb0, 0x6a, 0xdc, 0xad, 0xa8, 0xdb, 0x5f, 0xc2, 0x8d, 0x0b, 0x5b,
+	0xde, 0x0b, 0x9f, 0x00, 0x70, 0xa9, 0x4f, 0x83, 0x53, 0x3a, 0xfb, 0x8e, 0x3e, 0x04, 0x53, 0xd6,
+	0x53, 0xa9, 0xd9, 0x5d, 0x5b, 0x50, 0x40, 0xf3, 0x5a, 0xbb, 0x87, 0x0b, 0x57, 0x6d, 0x79, 0xf7,
+	0x34, 0x74, 0xe6, 0xa1, 0x97, 0x5b, 0xc5, 0xdb, 0x3c, 0x05, 0x78, 0x15, 0x79, 0xfe, 0x98, 0x8e,
+	0xae, 0xf9, 0x52, 0xc8, 0x7d, 0x68, 0x4a, 0x30, 0x2a, 0x3d, 0x2e, 0x03, 0xac, 0xf7, 0x20, 0x8e,
+	0xdc, 0x2e, 0xe2, 0xf8, 0x1e, 0x5a, 0xfb, 0x69, 0xe4, 0x5f, 0x17, 0x84, 0x2e, 0x80, 0x8d, 0xbc,
+	0x00, 0x3a, 0x8f, 0xa1, 0xa3, 0x0e, 0xc6, 0x37, 0x50, 0x60, 0x34, 0xde, 0x01, 0xe3, 0x13, 0xf5,
+	0xfe, 0x5e, 0x04, 0x5c, 0xb0, 0x24, 0x75, 0x55, 0x35, 0x95, 0x4d, 0xc5, 0x28, 0x35, 0x95, 0x05,
+	0x30, 0xc3, 0x60, 0x12, 0x88, 0xec, 0x86, 0x25, 0xe1, 0x7c, 0x05, 0xfd, 0x8a, 0xee, 0xfb, 0x5b,
+	0x17, 0x00, 0xfb, 0x34, 0x1a, 0x0d, 0xf9, 0xd1, 0x75, 0x83, 0x92, 0xbf, 0xa4, 0xc6, 0x3f, 0xbe,
+	0x24, 0xe7, 0x53, 0xe8, 0xe5, 0x56, 0x11, 0x33, 0x96, 0xd4, 0x91, 0xf6, 0xb7, 0xa6, 0x1a, 0x01,
+	0x17, 0x9e, 0x38, 0xe1, 0xda, 0x5d, 0x4d, 0x39, 0x8f, 0xa1, 0x9b, 0xf5, 0x10, 0x1d, 0xa8, 0x49,
+	0x30, 0x52, 0x8e, 0xd6, 0x5d, 0xb9, 0x46, 0xd5, 0x11, 0x15, 0x5e, 0x10, 0x66, 0x3d, 0x44, 0x51,
+	0xce, 0x4f, 0xd0, 0xda, 0xa1, 0xe9, 0x76, 0x74, 0xc8, 0x66, 0xe4, 0x4f, 0xe1, 0x53, 0xad, 0xe2,
+	0x93, 0x0d, 0xed, 0x38, 0xf4, 0xc4, 0x21, 0x4b, 0x26, 0xda, 0xdb, 0x9c, 0x9e, 0x6e, 0x03, 0x58,
+	0x9f, 0x75, 0xc5, 0xa4, 0xa3, 0xac, 0x3e, 0xe7, 0x0c, 0xe7, 0x17, 0x03, 0xda, 0x19, 0xf4, 0xf7,
+	0xe8, 0x7c, 0x0b, 0x60, 0xfa, 0xb2, 0x8e, 0xe8, 0x69, 0x44, 0x12, 0xd8, 0x24, 0x14, 0x40, 0x95,
+	0x05, 0x1d, 0x37, 0x23, 0xc9, 0x07, 0x7a, 0xf6, 0x30, 0xe5, 0xd5, 0xff, 0xa7, 0x5c, 0x76, 0xb4,
+	0xf7, 0x6a, 0x20, 0x71, 0x36, 0x8b, 0x6e, 0xac, 0xae, 0x60, 0x0d, 0x3a, 0xb1, 0x66, 0x4c, 0xbd,
+	0x1c, 0x3d, 0x48, 0x65, 0xbb, 0x8b, 0x6d, 0xce, 0x06, 0xb4, 0xbf, 0x63, 0x23, 0xca, 0xf1, 0x2e,
+	0xca, 0xa1, 0x32, 0xa6, 0x42, 0x65, 0x43, 0xdb, 0x0f, 0x03, 0x1a, 0x89, 0xed, 0x3d, 0x1d, 0xe0,
+	0x9c, 0x76, 0xfe, 0x34, 0x00, 0xf4, 0x21, 0x08, 0x03, 0xaf, 0x8f, 0x4d, 0xbc, 0x20, 0xca, 0x2a,
+	0xbd, 0xa2, 0xc8, 0x12, 0xb4, 0x85, 0x1f, 0xbf, 0x8e, 0x59, 0x92, 0xa5, 0x40, 0x4b, 0xf8, 0xf1,
+	0x1e, 0x4b, 0x04, 0xb9, 0x05, 0xad, 0x33, 0xae, 0x24, 0x2a, 0x4a, 0xcd, 0x33, 0x2e, 0x05, 0x4b,
+	0xd0, 0x3e, 0xe3, 0x5a, 0xa2, 0x72, 0xb5, 0x75, 0xc6, 0x95, 0xe8, 0x42, 0x33, 0x35, 0x4b, 0xcd,
+	0x14, 0xa3, 0x1e, 0x21, 0x24, 0x3d, 0xc2, 0x29, 0x82, 0x7c, 0x0c, 0xad, 0x03, 0xcf, 0x1f, 0xb3,
+	0xc3, 0x43, 0x39, 0xd5, 0x4c, 0x85, 0x77, 0x43, 0x89, 0xdc, 0x6c, 0x0f, 0xb9, 0x0b, 0x73, 0xf9,
+	0x89, 0xaf, 0x27, 0xde, 0xb9, 0x1e, 0xf5, 0x7a, 0x39, 0x73, 0xe8, 0x9d, 0x3b, 0x27, 0xd0, 0xd2,
+	0x8a, 0xe4, 0x36, 0x74, 0x26, 0xde, 0xf9, 0xeb, 0x11, 0x0d, 0x3d, 0xf5, 0x36, 0x4d, 0xb7, 0x3d,
+	0xf1, 0xce, 0xb7, 0x90, 0x26, 0xff, 0x05, 0x38, 0xf0, 0x38, 0xd5, 0x52, 0x3d, 0xb4, 0x22, 0x47,
+	0x89, 0x17, 0xa1, 0x79, 0xe8, 0xf9, 0x82, 0xa9, 0x9c, 0xac, 0xb9, 0x9a, 0x42, 0xfe, 0x8f, 0x81,
+	0x10, 0xba, 0x3e, 0xd7, 0x5c, 0x4d, 0x61, 0x1e, 0xed, 0x04, 0xfe, 0x78, 0x87, 0xa6, 0x5c, 0xe7,
+	0x91, 0x7c, 0x35, 0x46, 0x69, 0x62, 0x2d, 0xa6, 0xd1, 0x5a, 0x65, 0x1a, 0xd5, 0xaa, 0xc3, 0x60,
+	0xc4, 0xaf, 0x48, 0xc1, 0x99, 0xaa, 0x5d, 0xe8, 0xa0, 0xaa, 0x2a, 0xc5, 0x6f, 0xa1, 0xbf, 0x7e,
+	0x22, 0x8e, 0x59, 0x12, 0xbc, 0xa5, 0x58, 0xc3, 0xae, 0x5b, 0x7e, 0xb2, 0xa2, 0xd9, 0x28, 0x15,
+	0xcd, 0x45, 0x68, 0x8a, 0xc0, 0x1f, 0x53, 0xa1, 0xe7, 0x73, 0x4d, 0x39, 0xcf, 0x80, 0x4c, 0xd9,
+	0xc6, 0xa7, 0xb7, 0x00, 0xa6, 0x17, 0x86, 0xec, 0x4c, 0x0f, 0x39, 0x8a, 0xc0, 0x73, 0x7d, 0x36,
+	0xca, 0x3a, 0xab, 0x5c, 0xaf, 0xfd, 0xd5, 0x04, 0xf3, 0x5b, 0xbc, 0x75, 0xf2, 0x14, 0x5a, 0x7a,
+	0xf4, 0x23, 0x8b, 0xe5, 0xd7, 0x50, 0x0c, 0x9d, 0xb6, 0x35, 0x93, 0x8f, 0x06, 0xb7, 0x00, 0x8a,
+	0x31, 0x8b, 0x2c, 0x95, 0xf7, 0x55, 0x66, 0x39, 0xfb, 0xf6, 0x65, 0x22, 0x3c, 0x65, 0x1d, 0x3a,
+	0xf9, 0xec, 0x44, 0x2a, 0xc6, 0xca, 0xe3, 0x99, 0x6d, 0x5f, 0x22, 0xc1, 0x23, 0xbe, 0x80, 0xae,
+	0x4b, 0x23, 0x7a, 0xa6, 0x26, 0x13, 0x72, 0x73, 0xe6, 0x08, 0x65, 0xdf, 0xba, 0x64, 0x88, 0xc1,
+	0x20, 0xe8, 0x6e, 0x5f, 0x0d, 0x42, 0x31, 0x78, 0xd8, 0xd6, 0x4c, 0x3e, 0x2a, 0x3f, 0x04, 0x53,
+	0xa6, 0x3f, 0xa9, 0x54, 0x9b, 0xac, 0xac, 0xd8, 0x8b, 0x33, 0xb8, 0xda, 0xa6, 0xee, 0xec, 0x55,
+	0x9b, 0xc5, 0x98, 0x61, 0x5b, 0x33, 0xf9, 0xaa, 0xd6, 0x35, 0xb0, 0x5b, 0x93, 0x4a, 0x02, 0xeb,
+	0xc1, 0xc0, 0xbe, 0x79, 0x91, 0x89, 0x3a, 0x5f, 0x43, 0xb7, 0xd4, 0x6a, 0x49, 0x25, 0x9c, 0xd5,
+	0xfe, 0x6d, 0xdf, 0xb9, 0x54, 0xa6, 0x91, 0xeb, 0xde, 0x57, 0x45, 0x5e, 0xb4, 0x61, 0xdb, 0x9a,
+	0xc9, 0x47, 0xe5, 0x67, 0xa5, 0x2e, 0x72, 0x6b, 0x66, 0x79, 0xa6, 0x6f, 0xec, 0xa5, 0xd9, 0x02,
+	0xd4, 0x7f, 0x02, 0xed, 0x2c, 0xf1, 0xab, 0xfa, 0xa5, 0x72, 0x60, 0xdf, 0x9c, 0x16, 0x54, 0x74,
+	0x31, 0xf3, 0x2f, 0xea, 0xea, 0x7a, 0x70, 0x99, 0xee, 0x10, 0xe6, 0x2a, 0x19, 0x47, 0x2a, 0x31,
+	0x9a, 0x2e, 0x04, 0xf6, 0xff, 0xae, 0x90, 0xc6, 0x61, 0xba, 0xf1, 0xd1, 0x0f, 0xf7, 0xae, 0xfe,
+	0x6b, 0x20, 0x35, 0x9f, 0xca, 0xef, 0x81, 0x1a, 0x75, 0x1e, 0xfc, 0x3d, 0x00, 0xa0, 0x2e, 0x32,
+	0x2d, 0x88, 0x10, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -921,6 +2041,22 @@ type LogicClient interface {
 	Receive(ctx context.Context, in *ReceiveReq, opts ...grpc.CallOption) (*ReceiveReply, error)
 	//ServerList
 	Nodes(ctx context.Context, in *NodesReq, opts ...grpc.CallOption) (*NodesReply, error)
+	// Unacked
+	Unacked(ctx context.Context, in *UnackedReq, opts ...grpc.CallOption) (*UnackedReply, error)
+	// Sync
+	Sync(ctx context.Context, in *SyncReq, opts ...grpc.CallOption) (*SyncReply, error)
+	// RoomHistory
+	RoomHistory(ctx context.Context, in *RoomHistoryReq, opts ...grpc.CallOption) (*RoomHistoryReply, error)
+	// SendMsg
+	SendMsg(ctx context.Context, in *SendMsgReq, opts ...grpc.CallOption) (*SendMsgReply, error)
+	// Presence
+	Presence(ctx context.Context, in *PresenceReq, opts ...grpc.CallOption) (*PresenceReply, error)
+	// KickKeys
+	KickKeys(ctx context.Context, in *KickKeysReq, opts ...grpc.CallOption) (*KickReply, error)
+	// KickMids
+	KickMids(ctx context.Context, in *KickMidsReq, opts ...grpc.CallOption) (*KickReply, error)
+	// AuthorizeRoom
+	AuthorizeRoom(ctx context.Context, in *AuthorizeRoomReq, opts ...grpc.CallOption) (*AuthorizeRoomReply, error)
 }
 
 type logicClient struct {
@@ -985,6 +2121,78 @@ func (c *logicClient) Nodes(ctx context.Context, in *NodesReq, opts ...grpc.Call
 	return out, nil
 }
 
+func (c *logicClient) Unacked(ctx context.Context, in *UnackedReq, opts ...grpc.CallOption) (*UnackedReply, error) {
+	out := new(UnackedReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/Unacked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) Sync(ctx context.Context, in *SyncReq, opts ...grpc.CallOption) (*SyncReply, error) {
+	out := new(SyncReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/Sync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) RoomHistory(ctx context.Context, in *RoomHistoryReq, opts ...grpc.CallOption) (*RoomHistoryReply, error) {
+	out := new(RoomHistoryReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/RoomHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) SendMsg(ctx context.Context, in *SendMsgReq, opts ...grpc.CallOption) (*SendMsgReply, error) {
+	out := new(SendMsgReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/SendMsg", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) Presence(ctx context.Context, in *PresenceReq, opts ...grpc.CallOption) (*PresenceReply, error) {
+	out := new(PresenceReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/Presence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) KickKeys(ctx context.Context, in *KickKeysReq, opts ...grpc.CallOption) (*KickReply, error) {
+	out := new(KickReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/KickKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) KickMids(ctx context.Context, in *KickMidsReq, opts ...grpc.CallOption) (*KickReply, error) {
+	out := new(KickReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/KickMids", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logicClient) AuthorizeRoom(ctx context.Context, in *AuthorizeRoomReq, opts ...grpc.CallOption) (*AuthorizeRoomReply, error) {
+	out := new(AuthorizeRoomReply)
+	err := c.cc.Invoke(ctx, "/goim.logic.Logic/AuthorizeRoom", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogicServer is the server API for Logic service.
 type LogicServer interface {
 	// Connect
@@ -999,6 +2207,22 @@ type LogicServer interface {
 	Receive(context.Context, *ReceiveReq) (*ReceiveReply, error)
 	//ServerList
 	Nodes(context.Context, *NodesReq) (*NodesReply, error)
+	// Unacked
+	Unacked(context.Context, *UnackedReq) (*UnackedReply, error)
+	// Sync
+	Sync(context.Context, *SyncReq) (*SyncReply, error)
+	// RoomHistory
+	RoomHistory(context.Context, *RoomHistoryReq) (*RoomHistoryReply, error)
+	// SendMsg
+	SendMsg(context.Context, *SendMsgReq) (*SendMsgReply, error)
+	// Presence
+	Presence(context.Context, *PresenceReq) (*PresenceReply, error)
+	// KickKeys
+	KickKeys(context.Context, *KickKeysReq) (*KickReply, error)
+	// KickMids
+	KickMids(context.Context, *KickMidsReq) (*KickReply, error)
+	// AuthorizeRoom
+	AuthorizeRoom(context.Context, *AuthorizeRoomReq) (*AuthorizeRoomReply, error)
 }
 
 // UnimplementedLogicServer can be embedded to have forward compatible implementations.
@@ -1023,6 +2247,30 @@ func (*UnimplementedLogicServer) Receive(ctx context.Context, req *ReceiveReq) (
 func (*UnimplementedLogicServer) Nodes(ctx context.Context, req *NodesReq) (*NodesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Nodes not implemented")
 }
+func (*UnimplementedLogicServer) Unacked(ctx context.Context, req *UnackedReq) (*UnackedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unacked not implemented")
+}
+func (*UnimplementedLogicServer) Sync(ctx context.Context, req *SyncReq) (*SyncReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (*UnimplementedLogicServer) RoomHistory(ctx context.Context, req *RoomHistoryReq) (*RoomHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RoomHistory not implemented")
+}
+func (*UnimplementedLogicServer) SendMsg(ctx context.Context, req *SendMsgReq) (*SendMsgReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMsg not implemented")
+}
+func (*UnimplementedLogicServer) Presence(ctx context.Context, req *PresenceReq) (*PresenceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Presence not implemented")
+}
+func (*UnimplementedLogicServer) KickKeys(ctx context.Context, req *KickKeysReq) (*KickReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickKeys not implemented")
+}
+func (*UnimplementedLogicServer) KickMids(ctx context.Context, req *KickMidsReq) (*KickReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickMids not implemented")
+}
+func (*UnimplementedLogicServer) AuthorizeRoom(ctx context.Context, req *AuthorizeRoomReq) (*AuthorizeRoomReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthorizeRoom not implemented")
+}
 
 func RegisterLogicServer(s *grpc.Server, srv LogicServer) {
 	s.RegisterService(&_Logic_serviceDesc, srv)
@@ -1136,6 +2384,150 @@ func _Logic_Nodes_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Logic_Unacked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnackedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).Unacked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/Unacked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).Unacked(ctx, req.(*UnackedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/Sync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).Sync(ctx, req.(*SyncReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_RoomHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).RoomHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/RoomHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).RoomHistory(ctx, req.(*RoomHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_SendMsg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMsgReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).SendMsg(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/SendMsg",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).SendMsg(ctx, req.(*SendMsgReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_Presence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PresenceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).Presence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/Presence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).Presence(ctx, req.(*PresenceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_KickKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickKeysReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).KickKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/KickKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).KickKeys(ctx, req.(*KickKeysReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_KickMids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickMidsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).KickMids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/KickMids",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).KickMids(ctx, req.(*KickMidsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Logic_AuthorizeRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeRoomReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogicServer).AuthorizeRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goim.logic.Logic/AuthorizeRoom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogicServer).AuthorizeRoom(ctx, req.(*AuthorizeRoomReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Logic_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "goim.logic.Logic",
 	HandlerType: (*LogicServer)(nil),
@@ -1164,6 +2556,38 @@ var _Logic_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Nodes",
 			Handler:    _Logic_Nodes_Handler,
 		},
+		{
+			MethodName: "Unacked",
+			Handler:    _Logic_Unacked_Handler,
+		},
+		{
+			MethodName: "Sync",
+			Handler:    _Logic_Sync_Handler,
+		},
+		{
+			MethodName: "RoomHistory",
+			Handler:    _Logic_RoomHistory_Handler,
+		},
+		{
+			MethodName: "SendMsg",
+			Handler:    _Logic_SendMsg_Handler,
+		},
+		{
+			MethodName: "Presence",
+			Handler:    _Logic_Presence_Handler,
+		},
+		{
+			MethodName: "KickKeys",
+			Handler:    _Logic_KickKeys_Handler,
+		},
+		{
+			MethodName: "KickMids",
+			Handler:    _Logic_KickMids_Handler,
+		},
+		{
+			MethodName: "AuthorizeRoom",
+			Handler:    _Logic_AuthorizeRoom_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "logic/logic.proto",
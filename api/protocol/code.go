@@ -0,0 +1,27 @@
+package protocol
+
+// *踢人的原因码,放在OpDisconnectReply的消息体中发给客户端
+const (
+	KickReasonUnknown  = int32(0) //*未指明原因
+	KickReasonAdmin    = int32(1) //*被管理员踢下线
+	KickReasonBanned   = int32(2) //*账号被封禁
+	KickReasonReplaced = int32(3) //*在其他设备登录,被同一账号的新连接顶替
+	KickReasonDrain    = int32(4) //*comet下线,稍后重连到其他节点
+	KickReasonSlow     = int32(5) //*接收太慢,待发送的消息超过上限
+)
+
+// *点对点消息的发送状态,放在OpSendMsgReply的消息体中发给客户端
+const (
+	SendMsgOK      = int32(0) //*发送成功
+	SendMsgInvalid = int32(1) //*消息不合法,如目标用户为空或未登录
+	SendMsgFailed  = int32(2) //*服务端推送失败
+)
+
+// *加入房间的鉴权结果,放在OpChangeRoomReply和OpJoinRoomReply的消息体中发给客户端
+const (
+	RoomAuthOK     = int32(0) //*允许加入
+	RoomAuthDenied = int32(1) //*不在房间的允许名单中
+	RoomAuthTicket = int32(2) //*密码或票据错误
+	RoomAuthFailed = int32(3) //*鉴权失败,例如房间号无效或服务不可用
+	RoomAuthFull   = int32(4) //*连接加入的房间数已达上限,由comet判断
+)
@@ -46,4 +46,35 @@ const (
 	OpUnsub = int32(16)
 	//*用于表示取消订阅操作的回复
 	OpUnsubReply = int32(17)
+
+	//*用于表示需要客户端确认的推送,Seq为消息ID,Body为原始消息的完整协议包
+	OpPushMsg = int32(18)
+	//*用于表示客户端对推送的确认,Seq为消息ID
+	OpPushMsgAck = int32(19)
+
+	//*用于表示客户端从指定序列号开始同步消息,Body为序列号
+	OpSync = int32(20)
+	//*用于表示同步消息的回复,Body为序列号之后的消息的完整协议包
+	OpSyncReply = int32(21)
+
+	//*用于表示客户端获取房间最近的历史消息,Body为条数(主房间)或JSON: {"room":"live://1000","limit":20}
+	OpRoomHistory = int32(22)
+	//*用于表示房间历史消息的回复,Body为历史消息的完整协议包
+	OpRoomHistoryReply = int32(23)
+
+	//*用于表示关注的用户上线或下线的通知,Body为JSON格式的事件
+	OpPresenceChange = int32(24)
+
+	//*用于表示加入房间操作,不影响已加入的房间,Body为房间键或带票据的JSON
+	OpJoinRoom = int32(25)
+	//*用于表示加入房间操作的回复
+	OpJoinRoomReply = int32(26)
+
+	//*用于表示离开房间操作,Body为房间键
+	OpLeaveRoom = int32(27)
+	//*用于表示离开房间操作的回复
+	OpLeaveRoomReply = int32(28)
+
+	//*用于表示连接被转移到其他comet,Body为JSON格式的目标节点,服务端随后关闭连接
+	OpRedirect = int32(29)
 )
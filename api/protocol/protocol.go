@@ -61,6 +61,32 @@ func (p *Proto) WriteTo(b *bytes.Writer) {
 	}
 }
 
+// *从一个完整的协议包中解析协议,用于还原 OpRaw 的消息体
+func (p *Proto) Unpack(buf []byte) (err error) {
+	if len(buf) < _rawHeaderSize {
+		return ErrProtoPackLen
+	}
+	var (
+		packLen   = binary.BigEndian.Int32(buf[_packOffset:_headerOffset])
+		headerLen = binary.BigEndian.Int16(buf[_headerOffset:_verOffset])
+	)
+	if headerLen != _rawHeaderSize {
+		return ErrProtoHeaderLen
+	}
+	if packLen < int32(headerLen) || int(packLen) > len(buf) {
+		return ErrProtoPackLen
+	}
+	p.Ver = int32(binary.BigEndian.Int16(buf[_verOffset:_opOffset]))
+	p.Op = binary.BigEndian.Int32(buf[_opOffset:_seqOffset])
+	p.Seq = binary.BigEndian.Int32(buf[_seqOffset:])
+	if packLen > int32(headerLen) {
+		p.Body = buf[headerLen:packLen]
+	} else {
+		p.Body = nil
+	}
+	return
+}
+
 func (p *Proto) ReadTCP(rr *bufio.Reader) (err error) {
 	var (
 		bodyLen   int    //*消息体长度
@@ -20,6 +20,8 @@ import (
 	"github.com/gyy0727/mygoim/internal/comet"      //* comet 服务器相关
 	"github.com/gyy0727/mygoim/internal/comet/conf" //* 配置管理
 	"github.com/gyy0727/mygoim/internal/comet/grpc" //* gRPC 服务
+	"github.com/gyy0727/mygoim/pkg/confdiff"        //* 比较热加载前后的配置
+	"github.com/gyy0727/mygoim/pkg/discovery"       //* 服务发现节点格式
 
 	//* 数据模型定义
 	clientv3 "go.etcd.io/etcd/client/v3" //* etcd 客户端
@@ -40,6 +42,9 @@ func main() {
 	println(conf.Conf.Debug)
 	log.Infof("goim-comet [version: %s env: %+v] start", ver, conf.Conf.Env)
 
+	if err := comet.SetLogLevel(conf.Conf.Log.Level); err != nil {
+		panic(err)
+	}
 	//* 初始化 comet 服务器
 	srv := comet.NewServer(conf.Conf)
 	if err := comet.InitWhitelist(conf.Conf.Whitelist); err != nil {
@@ -57,6 +62,13 @@ func main() {
 		}
 	}
 
+	if err := comet.InitMetrics(srv, conf.Conf.Metrics); err != nil {
+		panic(err)
+	}
+	if err := comet.InitAdmin(srv, conf.Conf.Admin); err != nil {
+		panic(err)
+	}
+
 	//* 初始化 gRPC 服务
 	rpcSrv := grpc.New(conf.Conf.RPCServer, srv)
 
@@ -70,8 +82,8 @@ func main() {
 		log.Errorf("连接 etcd 失败: %v", err)
 		panic(err)
 	}
-	//* 注册服务，返回一个用于注销的 cancel 函数
-	cancel := registerEtcd(etcdCli, srv)
+	//* 注册服务，返回一个用于注销的 cancel 函数和重新发布 Env 的通道
+	cancel, envCh := registerEtcd(etcdCli, srv)
 
 	//* 处理系统信号，实现优雅退出
 	c := make(chan os.Signal, 1)
@@ -81,44 +93,87 @@ func main() {
 		log.Infof("goim-comet 收到信号 %s", s.String())
 		switch s {
 		case syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT:
+			//* 先在服务发现中下线，不再分配新连接，job 仍可向剩余连接推送
+			offline(envCh)
+			//* 停止接受新连接，分批通知客户端稍后重连并关闭连接
+			srv.Close()
 			if cancel != nil {
 				cancel() //* 注销服务：撤销 etcd 租约
 			}
 			rpcSrv.GracefulStop()
-			srv.Close()
 			log.Infof("goim-comet [version: %s] exit", ver)
 			log.Flush()
 			return
 		case syscall.SIGHUP:
-			//* 收到 SIGHUP 信号，可在此处添加配置重载代码
+			//* 收到 SIGHUP 信号，重新加载可以在运行时修改的配置
+			reload(srv, envCh)
 		default:
 			return
 		}
 	}
 }
 
-// registerEtcd 使用 etcd 进行服务注册，并定期更新服务元数据
-func registerEtcd(cli *clientv3.Client, srv *comet.Server) context.CancelFunc {
+// offline 在 etcd 中把本节点标记为下线，注册协程已退出时超时放弃
+func offline(envCh chan<- conf.Env) {
+	env := *conf.Conf.Env
+	env.Offline = true
+	select {
+	case envCh <- env:
+	case <-time.After(time.Second):
+		log.Errorf("标记下线超时，直接关闭连接")
+	}
+}
+
+// reload 重新解析并校验配置文件，记录变化的字段后热加载，Env.Offline 和 Env.Weight 重新发布到 etcd
+func reload(srv *comet.Server, envCh chan<- conf.Env) {
+	nc, err := conf.Load()
+	if err != nil {
+		log.Errorf("重新加载配置失败，保留当前配置: %v", err)
+		return
+	}
+	diffs, err := confdiff.Diff(conf.Conf, nc)
+	if err != nil {
+		log.Errorf("比较配置失败: %v", err)
+	}
+	for _, d := range diffs {
+		log.Infof("配置变化 %s", d)
+	}
+	srv.Reload(nc)
+	log.Infof("配置已重新加载，除 Protocol.HandshakeTimeout、Whitelist、Log.Level、Env.Offline、Env.Weight 外的修改需要重启生效")
+	select {
+	case envCh <- *nc.Env:
+	default:
+		log.Errorf("上一次的服务元数据还未发布，跳过本次发布")
+	}
+}
+
+// registerEtcd 使用 etcd 进行服务注册，并定期更新服务元数据，从返回的通道收到 Env 时立即重新发布
+func registerEtcd(cli *clientv3.Client, srv *comet.Server) (context.CancelFunc, chan<- conf.Env) {
 	env := conf.Conf.Env
 	addr := ip.InternalIP()
 	_, port, _ := net.SplitHostPort(conf.Conf.RPCServer.Addr)
 
 	//* 构造服务实例信息，使用 JSON 格式保存
 	instance := map[string]string{
-		"region":   env.Region,                        //* 所在区域
-		"zone":     env.Zone,                          //* 可用区
-		"env":      env.DeployEnv,                     //* 部署环境
-		"hostname": env.Host,                          //* 主机名
-		"appid":    appid,                             //* 应用 ID
-		"addr":     "grpc://" + addr + ":" + port,     //* 服务地址
-		"weight":   strconv.FormatInt(env.Weight, 10), //* 服务权重
-		"offline":  strconv.FormatBool(env.Offline),   //* 是否下线
-		"addrs":    strings.Join(env.Addrs, ","),      //* 其他地址
-	}
-
-	//* etcd 键的命名规则，此处使用 /services/{appid}/{hostname}，可根据实际需求调整
-	key := fmt.Sprintf("/services/%s/%s", appid, env.Host)
-	val, err := json.Marshal(instance)
+		"region":               env.Region,                        //* 所在区域
+		"zone":                 env.Zone,                          //* 可用区
+		"env":                  env.DeployEnv,                     //* 部署环境
+		discovery.MetaHostname: env.Host,                          //* 主机名
+		"appid":                appid,                             //* 应用 ID
+		"addr":                 "grpc://" + addr + ":" + port,     //* 服务地址
+		discovery.MetaWeight:   strconv.FormatInt(env.Weight, 10), //* 服务权重
+		discovery.MetaOffline:  strconv.FormatBool(env.Offline),   //* 是否下线
+		discovery.MetaAddrs:    strings.Join(env.Addrs, ","),      //* 其他地址
+	}
+
+	//* 按 pkg/discovery 的节点格式注册，job 和 logic 通过 EtcdResolver 发现 comet
+	node := &discovery.Node{
+		Name:     appid,
+		Addr:     addr + ":" + port,
+		Metadata: instance,
+	}
+	key := node.Key()
+	val, err := json.Marshal(node)
 	if err != nil {
 		log.Errorf("服务实例 JSON 编码失败: %v", err)
 		panic(err)
@@ -145,8 +200,22 @@ func registerEtcd(cli *clientv3.Client, srv *comet.Server) context.CancelFunc {
 		panic(err)
 	}
 
+	//* 将最新的服务实例信息写入 etcd
+	put := func() {
+		newVal, err := json.Marshal(node)
+		if err != nil {
+			log.Errorf("服务实例 JSON 编码失败: %v", err)
+			return
+		}
+		_, err = cli.Put(context.Background(), key, string(newVal), clientv3.WithLease(leaseResp.ID))
+		if err != nil {
+			log.Errorf("更新 etcd 中服务元数据失败: %v", err)
+		}
+	}
+
 	//* 使用一个上下文用于控制注销时退出续约和更新
 	ctx, cancel := context.WithCancel(context.Background())
+	envCh := make(chan conf.Env, 1)
 	go func() {
 		for {
 			select {
@@ -170,19 +239,17 @@ func registerEtcd(cli *clientv3.Client, srv *comet.Server) context.CancelFunc {
 					}
 					conns += bucket.ChannelCount()
 				}
-				instance["connCount"] = fmt.Sprintf("%d", conns)
+				instance[discovery.MetaConns] = fmt.Sprintf("%d", conns)
 				instance["ipCount"] = fmt.Sprintf("%d", len(ips))
-				newVal, err := json.Marshal(instance)
-				if err != nil {
-					log.Errorf("服务实例 JSON 编码失败: %v", err)
-					continue
-				}
-				_, err = cli.Put(context.Background(), key, string(newVal), clientv3.WithLease(leaseResp.ID))
-				if err != nil {
-					log.Errorf("更新 etcd 中服务元数据失败: %v", err)
-				}
+				put()
+			case env := <-envCh:
+				//* 热加载后立即发布新的权重和下线状态
+				instance[discovery.MetaWeight] = strconv.FormatInt(env.Weight, 10)
+				instance[discovery.MetaOffline] = strconv.FormatBool(env.Offline)
+				put()
+				log.Infof("重新发布服务元数据 weight:%d offline:%v", env.Weight, env.Offline)
 			}
 		}
 	}()
-	return cancel
+	return cancel, envCh
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/internal/job"
+	"github.com/gyy0727/mygoim/internal/job/conf"
+)
+
+const (
+	ver   = "2.0.0"
+	appid = "goim.job"
+)
+
+func main() {
+	flag.Parse()
+	if err := conf.Init(); err != nil {
+		panic(err)
+	}
+	log.Infof("goim-job [version: %s env: %+v] start", ver, conf.Conf.Env)
+	if err := job.InitMetrics(conf.Conf.Metrics); err != nil {
+		panic(err)
+	}
+	j := job.New(conf.Conf)
+	go j.Consume()
+	// signal
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT)
+	for {
+		s := <-c
+		log.Infof("goim-job get a signal %s", s.String())
+		switch s {
+		case syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT:
+			//*停止消费,排空comet推送管道并提交offset
+			if err := j.Close(); err != nil {
+				log.Errorf("job close error(%v)", err)
+			}
+			log.Infof("goim-job [version: %s] exit", ver)
+			log.Flush()
+			return
+		case syscall.SIGHUP:
+		default:
+			return
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gyy0727/mygoim/internal/logic/conf"
 	"github.com/gyy0727/mygoim/internal/logic/grpc"
 	"github.com/gyy0727/mygoim/internal/logic/http"
+	"github.com/gyy0727/mygoim/pkg/confdiff"
 )
 
 const (
@@ -42,8 +43,30 @@ func main() {
 			log.Flush()
 			return
 		case syscall.SIGHUP:
+			reload(srv)
 		default:
 			return
 		}
 	}
 }
+
+// reload re-read and validate the config file, log the diff and apply the settings that can change at runtime.
+func reload(srv *logic.Logic) {
+	nc, err := conf.Load()
+	if err != nil {
+		log.Errorf("reload config error(%v), keep the current one", err)
+		return
+	}
+	diffs, err := confdiff.Diff(conf.Conf, nc)
+	if err != nil {
+		log.Errorf("confdiff.Diff() error(%v)", err)
+	}
+	if err = srv.Reload(nc); err != nil {
+		log.Errorf("reload config error(%v), keep the current one", err)
+		return
+	}
+	for _, d := range diffs {
+		log.Infof("config changed %s", d)
+	}
+	log.Info("config reloaded, changes other than Regions, Backoff and Auth need a restart, logic has no Node settings to reload")
+}
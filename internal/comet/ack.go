@@ -0,0 +1,187 @@
+package comet
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+	"github.com/gyy0727/mygoim/pkg/bytes"
+	xtime "github.com/gyy0727/mygoim/pkg/time"
+	"go.uber.org/zap"
+)
+
+// *等待客户端确认的消息
+type ackMsg struct {
+	id    int32           //*消息ID
+	p     *protocol.Proto //*原始消息
+	wrap  *protocol.Proto //*带消息ID的推送
+	sent  time.Time       //*最近一次发送的时间
+	retry int             //*已重传的次数
+}
+
+// *连接上未确认消息的窗口
+type ackWindow struct {
+	mutex  sync.Mutex
+	seq    int32     //*最近分配的消息ID
+	size   int       //*窗口大小
+	msgs   []*ackMsg //*按发送顺序排列的未确认消息
+	closed bool      //*连接是否已断开
+}
+
+func newAckWindow(size int) *ackWindow {
+	return &ackWindow{size: size, msgs: make([]*ackMsg, 0, size)}
+}
+
+// *把原始消息包装成带消息ID的推送
+func wrapAck(id int32, p *protocol.Proto) *protocol.Proto {
+	w := bytes.NewWriterSize(len(p.Body) + 16)
+	p.WriteTo(w)
+	return &protocol.Proto{Ver: p.Ver, Op: protocol.OpPushMsg, Seq: id, Body: w.Buffer()}
+}
+
+// *放入窗口并推送,窗口已满或连接已断开时返回ErrAckWindowFull
+func (w *ackWindow) push(ch *Channel, p *protocol.Proto) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed || len(w.msgs) >= w.size {
+		return errors.ErrAckWindowFull
+	}
+	if w.seq++; w.seq <= 0 {
+		w.seq = 1
+	}
+	m := &ackMsg{id: w.seq, p: p, wrap: wrapAck(w.seq, p), sent: time.Now()}
+	w.msgs = append(w.msgs, m)
+	//*信号通道满时消息留在窗口里等待重传
+	_ = ch.Push(m.wrap)
+	return nil
+}
+
+// *收到客户端确认,从窗口中移除
+func (w *ackWindow) ack(id int32) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for i, m := range w.msgs {
+		if m.id == id {
+			w.msgs = append(w.msgs[:i], w.msgs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// *重传超时未确认的消息,有消息超过重试次数时返回false
+func (w *ackWindow) retransmit(ch *Channel, timeout time.Duration, retry int, reset func()) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return true
+	}
+	now := time.Now()
+	for _, m := range w.msgs {
+		if now.Sub(m.sent) < timeout {
+			continue
+		}
+		if m.retry >= retry {
+			return false
+		}
+		m.retry++
+		m.sent = now
+		_ = ch.Push(m.wrap)
+	}
+	//*持有锁时重置定时器,保证close之后不会再被加回定时器
+	reset()
+	return true
+}
+
+// *关闭窗口,返回所有未确认的原始消息
+func (w *ackWindow) close() (protos []*protocol.Proto) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.closed = true
+	for _, m := range w.msgs {
+		protos = append(protos, m.p)
+	}
+	w.msgs = nil
+	return
+}
+
+// *判断操作码是否需要客户端确认
+func (s *Server) NeedAck(op int32) bool {
+	_, ok := s.ackOps[op]
+	return ok
+}
+
+// *推送需要客户端确认的消息,窗口已满时直接交给logic存为离线消息
+func (s *Server) PushAck(ch *Channel, p *protocol.Proto) (err error) {
+	if ch.ack == nil {
+		return ch.Push(p)
+	}
+	//*job推送的是打包好的OpRaw,先还原出原始消息
+	if p.Op == protocol.OpRaw {
+		raw := new(protocol.Proto)
+		if err = raw.Unpack(p.Body); err != nil {
+			return
+		}
+		p = raw
+	}
+	if err = ch.ack.push(ch, p); err != nil {
+		logger.Warn("ack window full, hand back to logic",
+			zap.String("key", ch.Key),
+			zap.Int64("mid", ch.Mid),
+			zap.Int32("op", p.Op),
+		)
+		go s.Unacked(context.Background(), ch.Mid, ch.Key, []*protocol.Proto{p})
+	}
+	return nil
+}
+
+// *收到客户端的确认
+func (s *Server) Ack(ch *Channel, id int32) {
+	if ch.ack == nil {
+		return
+	}
+	if !ch.ack.ack(id) && conf.Conf.Debug {
+		logger.Info("ack unknown msg id",
+			zap.String("key", ch.Key),
+			zap.Int32("id", id),
+		)
+	}
+}
+
+// *连接认证成功后开启消息确认,定时重传未确认的消息,超过重试次数断开连接
+func (s *Server) startAck(ch *Channel, tr *xtime.Timer, conn io.Closer) (trd *xtime.TimerData) {
+	if len(s.ackOps) == 0 {
+		return
+	}
+	var (
+		timeout = time.Duration(s.c.Ack.Timeout)
+		retry   = s.c.Ack.Retry
+	)
+	ch.ack = newAckWindow(s.c.Ack.Window)
+	trd = tr.Add(timeout, func() {
+		if !ch.ack.retransmit(ch, timeout, retry, func() { tr.Set(trd, timeout) }) {
+			conn.Close()
+			logger.Error("ack retry exceeded, close conn",
+				zap.String("key", ch.Key),
+				zap.Int64("mid", ch.Mid),
+				zap.Int("retry", retry),
+			)
+		}
+	})
+	trd.Key = ch.Key
+	return
+}
+
+// *连接断开时关闭消息确认,返回未确认的消息
+func (s *Server) stopAck(ch *Channel, tr *xtime.Timer, trd *xtime.TimerData) []*protocol.Proto {
+	if ch.ack == nil {
+		return nil
+	}
+	protos := ch.ack.close()
+	tr.Del(trd)
+	return protos
+}
@@ -0,0 +1,71 @@
+package comet
+
+import (
+	stdbytes "bytes"
+	"testing"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/pkg/bytes"
+)
+
+// 测试未确认消息窗口的推送、确认、重传和关闭
+func TestAckWindow(t *testing.T) {
+	var (
+		ch = NewChannel(5, 10)
+		w  = newAckWindow(2)
+	)
+	for i := int32(1); i <= 3; i++ {
+		err := w.push(ch, &protocol.Proto{Op: 1000, Body: []byte("msg")})
+		if i <= 2 && err != nil {
+			t.Fatalf("push %d error(%v)", i, err)
+		}
+		if i == 3 && err == nil {
+			t.Fatal("push to full window want error")
+		}
+	}
+	if p := ch.Ready(); p.Op != protocol.OpPushMsg || p.Seq != 1 {
+		t.Errorf("pushed proto op:%d seq:%d; want op:%d seq:1", p.Op, p.Seq, protocol.OpPushMsg)
+	}
+	ch.Ready()
+	if !w.ack(1) || w.ack(1) {
+		t.Error("ack(1) want true then false")
+	}
+	reset := 0
+	if !w.retransmit(ch, 0, 1, func() { reset++ }) || reset != 1 {
+		t.Error("first retransmit want ok")
+	}
+	if p := ch.Ready(); p.Seq != 2 {
+		t.Errorf("retransmit seq:%d; want 2", p.Seq)
+	}
+	if w.retransmit(ch, 0, 1, func() { reset++ }) {
+		t.Error("retransmit over retry want false")
+	}
+	protos := w.close()
+	if len(protos) != 1 || protos[0].Op != 1000 {
+		t.Errorf("close got %d protos; want 1 with op 1000", len(protos))
+	}
+	if err := w.push(ch, &protocol.Proto{Op: 1000}); err == nil {
+		t.Error("push after close want error")
+	}
+}
+
+// 测试job打包的OpRaw消息在确认推送前被还原
+func TestPushAckRaw(t *testing.T) {
+	var (
+		s  = &Server{}
+		ch = NewChannel(5, 10)
+		w  = bytes.NewWriterSize(64)
+	)
+	ch.ack = newAckWindow(2)
+	(&protocol.Proto{Ver: 1, Op: 1000, Body: []byte("msg")}).WriteTo(w)
+	if err := s.PushAck(ch, &protocol.Proto{Ver: 1, Op: protocol.OpRaw, Body: w.Buffer()}); err != nil {
+		t.Fatal(err)
+	}
+	p := ch.Ready()
+	if p.Op != protocol.OpPushMsg || !stdbytes.Equal(p.Body, w.Buffer()) {
+		t.Errorf("pushed proto op:%d body:%v; want op:%d body:%v", p.Op, p.Body, protocol.OpPushMsg, w.Buffer())
+	}
+	if protos := ch.ack.close(); len(protos) != 1 || protos[0].Op != 1000 || string(protos[0].Body) != "msg" {
+		t.Errorf("unacked protos:%v; want op 1000 body msg", protos)
+	}
+}
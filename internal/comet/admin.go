@@ -0,0 +1,382 @@
+package comet
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+	xstrings "github.com/gyy0727/mygoim/pkg/strings"
+	"go.uber.org/zap"
+)
+
+const (
+	// *管理接口的返回码,与logic的http接口一致
+	adminOK         = 0
+	adminRequestErr = -400
+	adminAuthErr    = -401
+	// *房间查看默认和最多返回的连接数
+	adminRoomLimit    = 100
+	adminRoomMaxLimit = 1000
+	// *配置中需要隐藏的字段
+	adminMask = "******"
+)
+
+// *管理接口的返回格式
+type adminResp struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// *连接信息
+type adminConn struct {
+	Key    string   `json:"key"`
+	Mid    int64    `json:"mid"`
+	IP     string   `json:"ip"`
+	Bucket int      `json:"bucket"`
+	Room   string   `json:"room"`  //*主房间
+	Rooms  []string `json:"rooms"` //*加入的所有房间
+}
+
+// *房间信息
+type adminRoom struct {
+	ID        string       `json:"id"`
+	Online    int32        `json:"online"`     //*本机在线数
+	AllOnline int32        `json:"all_online"` //*logic汇总的全局在线数
+	Buckets   map[int]int  `json:"buckets"`    //*每个bucket中的连接数
+	Conns     []*adminConn `json:"conns"`
+}
+
+// *开启管理接口时启动http服务,只监听配置的地址并校验token,没有配置token时拒绝启动
+func InitAdmin(s *Server, c *conf.Admin) (err error) {
+	if !c.Open {
+		return
+	}
+	if c.Token == "" {
+		return errors.ErrAdminToken
+	}
+	lis, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		logger.Error("Failed to listen on admin address",
+			zap.String("addr", c.Addr),
+			zap.Error(err),
+		)
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/conns", s.adminConns)
+	mux.HandleFunc("/room", s.adminRoom)
+	mux.HandleFunc("/kick", s.adminKick)
+	mux.HandleFunc("/config", s.adminConfig)
+	mux.HandleFunc("/whitelist", s.adminWhitelist)
+	mux.HandleFunc("/whitelist/add", s.adminWhitelistAdd)
+	mux.HandleFunc("/whitelist/del", s.adminWhitelistDel)
+	logger.Info("Start admin listen", zap.String("addr", c.Addr))
+	go func() {
+		if err := http.Serve(lis, adminAuth(c.Token, mux)); err != nil {
+			logger.Error("admin server exit", zap.Error(err))
+		}
+	}()
+	return
+}
+
+// *校验请求头X-Admin-Token,不接受url参数,避免token出现在访问日志和浏览器历史中
+func adminAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t := r.Header.Get("X-Admin-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(t), []byte(token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			adminWrite(w, adminResp{Code: adminAuthErr, Message: "invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func adminWrite(w http.ResponseWriter, resp adminResp) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func adminResult(w http.ResponseWriter, data interface{}) {
+	adminWrite(w, adminResp{Code: adminOK, Data: data})
+}
+
+func adminError(w http.ResponseWriter, msg string) {
+	adminWrite(w, adminResp{Code: adminRequestErr, Message: msg})
+}
+
+// *返回key所在的bucket序号
+func (s *Server) bucketIndex(key string) int {
+	b := s.Bucket(key)
+	for i, bb := range s.buckets {
+		if bb == b {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) adminConn(ch *Channel) *adminConn {
+	conn := &adminConn{Key: ch.Key, Mid: ch.Mid, IP: ch.IP, Bucket: s.bucketIndex(ch.Key), Rooms: []string{}}
+	if room := ch.Room(); room != nil {
+		conn.Room = room.ID
+	}
+	for _, room := range ch.Rooms() {
+		conn.Rooms = append(conn.Rooms, room.ID)
+	}
+	sort.Strings(conn.Rooms)
+	return conn
+}
+
+// *按key、mid或ip查找本机的连接: GET /conns?key=xxx 或 ?mid=123 或 ?ip=1.2.3.4
+func (s *Server) adminConns(w http.ResponseWriter, r *http.Request) {
+	var (
+		q     = r.URL.Query()
+		chs   []*Channel
+		conns = make([]*adminConn, 0)
+	)
+	switch {
+	case q.Get("key") != "":
+		if ch := s.Bucket(q.Get("key")).Channel(q.Get("key")); ch != nil {
+			chs = append(chs, ch)
+		}
+	case q.Get("mid") != "":
+		mid, err := strconv.ParseInt(q.Get("mid"), 10, 64)
+		if err != nil {
+			adminError(w, "invalid mid")
+			return
+		}
+		for _, b := range s.buckets {
+			chs = append(chs, b.MidChannels(mid)...)
+		}
+	case q.Get("ip") != "":
+		for _, b := range s.buckets {
+			chs = append(chs, b.IPChannels(q.Get("ip"))...)
+		}
+	default:
+		adminError(w, "key, mid or ip is required")
+		return
+	}
+	for _, ch := range chs {
+		conns = append(conns, s.adminConn(ch))
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].Key < conns[j].Key })
+	adminResult(w, conns)
+}
+
+// *查看房间在本机的在线情况: GET /room?id=live://1000&limit=100
+func (s *Server) adminRoom(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rid := q.Get("id")
+	if rid == "" {
+		adminError(w, "id is required")
+		return
+	}
+	limit := adminRoomLimit
+	if l, err := strconv.Atoi(q.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > adminRoomMaxLimit {
+		limit = adminRoomMaxLimit
+	}
+	var (
+		res = &adminRoom{ID: rid, Buckets: make(map[int]int), Conns: make([]*adminConn, 0)}
+		chs []*Channel
+	)
+	for i, b := range s.buckets {
+		room := b.Room(rid)
+		if room == nil {
+			continue
+		}
+		bchs := room.Channels()
+		res.Online += int32(len(bchs))
+		res.AllOnline = room.AllOnline
+		res.Buckets[i] = len(bchs)
+		chs = append(chs, bchs...)
+	}
+	sort.Slice(chs, func(i, j int) bool { return chs[i].Key < chs[j].Key })
+	if len(chs) > limit {
+		chs = chs[:limit]
+	}
+	for _, ch := range chs {
+		res.Conns = append(res.Conns, s.adminConn(ch))
+	}
+	adminResult(w, res)
+}
+
+// *踢掉本机的连接: POST /kick?key=xxx 或 ?mid=123,可选reason
+func (s *Server) adminKick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		adminError(w, "method not allowed")
+		return
+	}
+	var (
+		q      = r.URL.Query()
+		reason = protocol.KickReasonAdmin
+		keys   []string
+	)
+	if v := q.Get("reason"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			adminError(w, "invalid reason")
+			return
+		}
+		reason = int32(n)
+	}
+	switch {
+	case q.Get("key") != "":
+		keys = append(keys, q.Get("key"))
+	case q.Get("mid") != "":
+		mid, err := strconv.ParseInt(q.Get("mid"), 10, 64)
+		if err != nil {
+			adminError(w, "invalid mid")
+			return
+		}
+		for _, b := range s.buckets {
+			for _, ch := range b.MidChannels(mid) {
+				keys = append(keys, ch.Key)
+			}
+		}
+	default:
+		adminError(w, "key or mid is required")
+		return
+	}
+	var count int
+	for _, key := range keys {
+		if s.Kick(key, reason) {
+			count++
+		}
+	}
+	logger.Info("admin kick",
+		zap.Strings("keys", keys),
+		zap.Int32("reason", reason),
+		zap.Int("count", count),
+	)
+	adminResult(w, map[string]int{"count": count})
+}
+
+// *返回当前配置,隐藏密码和token
+func (s *Server) adminConfig(w http.ResponseWriter, r *http.Request) {
+	s.confMutex.RLock()
+	defer s.confMutex.RUnlock()
+	c := *s.c
+	if c.Etcd != nil {
+		etcd := *c.Etcd
+		if etcd.Password != "" {
+			etcd.Password = adminMask
+		}
+		c.Etcd = &etcd
+	}
+	if c.Admin != nil {
+		admin := *c.Admin
+		if admin.Token != "" {
+			admin.Token = adminMask
+		}
+		c.Admin = &admin
+	}
+	adminResult(w, &c)
+}
+
+// *返回白名单中未过期的用户和前缀
+func (s *Server) adminWhitelist(w http.ResponseWriter, r *http.Request) {
+	entries := make([]*WhiteEntry, 0)
+	if whitelist != nil {
+		entries = append(entries, whitelist.List()...)
+	}
+	adminResult(w, map[string]interface{}{"entries": entries, "log": s.c.Whitelist.WhiteLog})
+}
+
+// *解析白名单参数: mids=1,2&prefixes=web-,ios-
+func adminWhitelistArg(r *http.Request) (mids []int64, prefixes []string, err error) {
+	q := r.URL.Query()
+	if v := q.Get("mids"); v != "" {
+		if mids, err = xstrings.SplitInt64s(v, ","); err != nil {
+			return
+		}
+	}
+	if v := q.Get("prefixes"); v != "" {
+		prefixes = strings.Split(v, ",")
+	}
+	return
+}
+
+// *添加白名单: POST /whitelist/add?mids=1,2&prefixes=web-&sample=10&ttl=10m,添加前缀时必须带上1-100的sample
+func (s *Server) adminWhitelistAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		adminError(w, "method not allowed")
+		return
+	}
+	var (
+		q      = r.URL.Query()
+		sample int
+		ttl    time.Duration
+	)
+	mids, prefixes, err := adminWhitelistArg(r)
+	if err != nil {
+		adminError(w, "invalid mids")
+		return
+	}
+	if v := q.Get("sample"); v != "" {
+		if sample, err = strconv.Atoi(v); err != nil || sample < 1 || sample > 100 {
+			adminError(w, "invalid sample")
+			return
+		}
+	}
+	if v := q.Get("ttl"); v != "" {
+		if ttl, err = time.ParseDuration(v); err != nil {
+			adminError(w, "invalid ttl")
+			return
+		}
+	}
+	if err = s.AddWhitelist(mids, prefixes, sample, ttl); err != nil {
+		adminError(w, err.Error())
+		return
+	}
+	logger.Info("admin whitelist add",
+		zap.Int64s("mids", mids),
+		zap.Strings("prefixes", prefixes),
+		zap.Int("sample", sample),
+		zap.Duration("ttl", ttl),
+	)
+	adminResult(w, nil)
+}
+
+// *移除白名单: POST /whitelist/del?mids=1,2&prefixes=web-
+func (s *Server) adminWhitelistDel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		adminError(w, "method not allowed")
+		return
+	}
+	mids, prefixes, err := adminWhitelistArg(r)
+	if err != nil {
+		adminError(w, "invalid mids")
+		return
+	}
+	if err = s.DelWhitelist(mids, prefixes); err != nil {
+		adminError(w, err.Error())
+		return
+	}
+	logger.Info("admin whitelist del",
+		zap.Int64s("mids", mids),
+		zap.Strings("prefixes", prefixes),
+	)
+	adminResult(w, nil)
+}
@@ -6,6 +6,7 @@ import (
 	pb "github.com/gyy0727/mygoim/api/comet"
 	"github.com/gyy0727/mygoim/api/protocol"
 	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
 	"go.uber.org/zap"
 )
 
@@ -58,87 +59,96 @@ func (b *Bucket) RoomsCount() (res map[string]int32) {
 	b.cLock.RLock()
 	res = make(map[string]int32)
 	for roomID, room = range b.rooms {
-		if room.Online > 0 {
-			res[roomID] = room.Online
+		if online := room.OnlineCount(); online > 0 {
+			res[roomID] = online
 		}
 	}
 	b.cLock.RUnlock()
 	return
 }
 
-// *将channel迁移到新房间
+// *将channel的主房间切换到新房间,nrid为空时只离开原来的主房间,已加入的其他房间不受影响
 func (b *Bucket) ChangeRoom(nrid string, ch *Channel) (err error) {
-	var (
-		nroom *Room
-		ok    bool
-		oroom = ch.Room
-	)
-	//*删除房间
+	oroom := ch.Room()
+	if oroom != nil && oroom.ID == nrid {
+		return
+	}
+	if oroom != nil {
+		b.LeaveRoom(oroom.ID, ch)
+	}
 	if nrid == "" {
-		//*将通道从原来的房间移除
-		if oroom != nil && oroom.Del(ch) {
-			//*房间无在线人数
-			b.DelRoom(oroom)
+		return
+	}
+	//*已经作为其他房间加入过的直接提升为主房间
+	nroom := ch.InRoom(nrid)
+	if nroom == nil {
+		if nroom, err = b.joinRoom(nrid, ch); err != nil {
+			return
 		}
-		ch.Room = nil
+	}
+	ch.setRoom(nroom)
+	logger.Info("channel迁移房间", zap.String("channel", ch.Key), zap.String("room", nrid))
+	return
+}
+
+// *加入房间,不影响已加入的房间,max为连接最多可加入的房间数,<=0表示不限制
+func (b *Bucket) JoinRoom(rid string, ch *Channel, max int) (err error) {
+	if ch.InRoom(rid) != nil {
 		return
 	}
+	if max > 0 && ch.RoomNum() >= max {
+		return errors.ErrRoomFull
+	}
+	_, err = b.joinRoom(rid, ch)
+	return
+}
+
+func (b *Bucket) joinRoom(rid string, ch *Channel) (room *Room, err error) {
+	var ok bool
 	b.cLock.Lock()
-	//*检查nrid对应的房间是否存在
-	if nroom, ok = b.rooms[nrid]; !ok {
-		nroom = NewRoom(nrid)
-		b.rooms[nrid] = nroom
+	//*检查rid对应的房间是否存在
+	if room, ok = b.rooms[rid]; !ok {
+		room = NewRoom(rid)
+		b.rooms[rid] = room
 	}
 	b.cLock.Unlock()
-	if oroom != nil && oroom.Del(ch) {
-		b.DelRoom(oroom)
-	}
-	//*添加到新房间
-	if err = nroom.Put(ch); err != nil {
+	if err = room.Put(ch); err != nil {
 		return
 	}
-	ch.Room = nroom
-	logger.Info("channel迁移房间", zap.String("channel", ch.Key))
+	ch.addRoom(room)
 	return
 }
 
+// *离开房间,房间无在线人数时删除房间
+func (b *Bucket) LeaveRoom(rid string, ch *Channel) {
+	if room := ch.delRoom(rid); room != nil && room.Del(ch) {
+		b.DelRoom(room)
+	}
+}
+
 // *添加连接
 func (b *Bucket) Put(rid string, ch *Channel) (err error) {
-	var (
-		room *Room
-		ok   bool
-	)
 	b.cLock.Lock()
-	//*关闭旧通道（如果存在相同Key的通道）
-	if dch := b.chs[ch.Key]; dch != nil {
-		dch.Close()
-	}
+	dch := b.chs[ch.Key]
 	//*将新通道存入Bucket的chs映射
 	b.chs[ch.Key] = ch
-	if rid != "" {
-		//*rid对应的房间不存在
-		if room, ok = b.rooms[rid]; !ok {
-			//*创建房间
-			room = NewRoom(rid)
-			b.rooms[rid] = room
-		}
-		//*关联通道
-		ch.Room = room
-	}
 	//*添加ip对应的链接计数
 	b.ipCnts[ch.IP]++
 	b.cLock.Unlock()
-	if room != nil {
-		//*将通道添加到房间中
-		err = room.Put(ch)
+	//*关闭旧通道（如果存在相同Key的通道）,Close可能阻塞,放在锁外面
+	if dch != nil {
+		dch.Close()
+	}
+	if rid != "" {
+		//*加入主房间
+		err = b.ChangeRoom(rid, ch)
 	}
 	logger.Info("bucket添加channel", zap.String("channel", ch.Key))
 	return
 }
 
-// *删除key对应的通道,从chs[]和dch对应的room中删除
+// *删除key对应的通道,从chs[]和dch加入的所有room中删除
 func (b *Bucket) Del(dch *Channel) {
-	room := dch.Room
 	b.cLock.Lock()
 	//*如果存在该key
 	if ch, ok := b.chs[dch.Key]; ok {
@@ -155,9 +165,9 @@ func (b *Bucket) Del(dch *Channel) {
 		}
 	}
 	b.cLock.Unlock()
-	if room != nil && room.Del(dch) {
-		//*房间已经没有活跃用户了,移除房间
-		b.DelRoom(room)
+	//*离开所有加入的房间,房间已经没有活跃用户了就移除房间
+	for _, room := range dch.Rooms() {
+		b.LeaveRoom(room.ID, dch)
 	}
 	logger.Info("bucket删除channel", zap.String("channel", dch.Key))
 }
@@ -170,6 +180,43 @@ func (b *Bucket) Channel(key string) (ch *Channel) {
 	return
 }
 
+// *返回本bucket中的所有通道
+func (b *Bucket) Channels() (chs []*Channel) {
+	b.cLock.RLock()
+	chs = make([]*Channel, 0, len(b.chs))
+	for _, ch := range b.chs {
+		chs = append(chs, ch)
+	}
+	b.cLock.RUnlock()
+	return
+}
+
+// *返回用户mid在本bucket中的所有通道
+func (b *Bucket) MidChannels(mid int64) (chs []*Channel) {
+	b.cLock.RLock()
+	for _, ch := range b.chs {
+		if ch.Mid == mid {
+			chs = append(chs, ch)
+		}
+	}
+	b.cLock.RUnlock()
+	return
+}
+
+// *返回来自ip的所有通道,ip在本bucket没有连接时直接返回
+func (b *Bucket) IPChannels(ip string) (chs []*Channel) {
+	b.cLock.RLock()
+	if b.ipCnts[ip] > 0 {
+		for _, ch := range b.chs {
+			if ch.IP == ip {
+				chs = append(chs, ch)
+			}
+		}
+	}
+	b.cLock.RUnlock()
+	return
+}
+
 // *广播消息到bucket的每个channel中
 func (b *Bucket) Broadcast(p *protocol.Proto, op int32) {
 	logger.Info("bucket广播消息", zap.Int32("op", op))
@@ -216,7 +263,7 @@ func (b *Bucket) Rooms() (res map[string]struct{}) {
 	res = make(map[string]struct{})
 	b.cLock.RLock()
 	for roomID, room = range b.rooms {
-		if room.Online > 0 {
+		if room.OnlineCount() > 0 {
 			res[roomID] = struct{}{}
 		}
 	}
@@ -0,0 +1,56 @@
+package comet
+
+import (
+	"testing"
+
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+)
+
+// 测试一个连接同时加入多个房间时的在线统计和清理
+func TestBucketMultiRoom(t *testing.T) {
+	b := NewBucket(&conf.Bucket{Channel: 8, Room: 8, RoutineAmount: 1, RoutineSize: 1})
+	ch1, ch2 := NewChannel(5, 10), NewChannel(5, 10)
+	ch1.Key, ch2.Key = "key1", "key2"
+	if err := b.Put("live://1", ch1); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put("live://1", ch2); err != nil {
+		t.Fatal(err)
+	}
+	for _, rid := range []string{"group://1", "notify://1"} {
+		if err := b.JoinRoom(rid, ch1, 3); err != nil {
+			t.Fatalf("join %s error(%v)", rid, err)
+		}
+	}
+	if err := b.JoinRoom("group://2", ch1, 3); err != errors.ErrRoomFull {
+		t.Fatalf("join over max error(%v); want ErrRoomFull", err)
+	}
+	if err := b.JoinRoom("group://1", ch2, 3); err != nil {
+		t.Fatal(err)
+	}
+	counts := b.RoomsCount()
+	if counts["live://1"] != 2 || counts["group://1"] != 2 || counts["notify://1"] != 1 {
+		t.Fatalf("rooms count %v", counts)
+	}
+	// 切换主房间不影响其他房间
+	if err := b.ChangeRoom("group://1", ch1); err != nil {
+		t.Fatal(err)
+	}
+	if ch1.Room() == nil || ch1.Room().ID != "group://1" || ch1.RoomNum() != 2 {
+		t.Fatalf("change room primary:%v rooms:%d", ch1.Room(), ch1.RoomNum())
+	}
+	b.LeaveRoom("notify://1", ch1)
+	if b.Room("notify://1") != nil {
+		t.Fatal("empty room not deleted")
+	}
+	b.Del(ch1)
+	counts = b.RoomsCount()
+	if len(counts) != 2 || counts["live://1"] != 1 || counts["group://1"] != 1 {
+		t.Fatalf("rooms count after del %v", counts)
+	}
+	b.Del(ch2)
+	if b.RoomCount() != 0 {
+		t.Fatalf("room count after del all %d", b.RoomCount())
+	}
+}
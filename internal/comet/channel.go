@@ -1,6 +1,7 @@
 package comet
 
 import (
+	"io"
 	"sync"
 
 	"github.com/gyy0727/mygoim/api/protocol"
@@ -10,18 +11,23 @@ import (
 )
 
 type Channel struct {
-	Room     *Room                //*关联的房间
+	room     *Room                //*主房间,连接时加入或通过OpChangeRoom切换,读写需持有mutex
 	CliProto Ring                 //*客户端协议缓冲区（环形缓冲区）
 	signal   chan *protocol.Proto //* 用于传递协议消息的信号通道
 	Writer   bufio.Writer         //*用于写入数据的缓冲区
 	Reader   bufio.Reader         //*用于读取数据的缓冲区
-	Next     *Channel             //*双向链表中的下一个 Channel
-	Prev     *Channel             //*双向链表中的上一个 Channel
 	Mid      int64                //*用户 ID
 	Key      string               //*当前链接的唯一标识
 	IP       string               //*客户端IP地址
 	watchOps map[int32]struct{}   //*监听的操作集合
-	mutex    sync.RWMutex         //*读写锁，用于保护 watchOps 的并发访问
+	rooms    map[string]*Room     //*加入的所有房间,包含主房间
+	mutex    sync.RWMutex         //*读写锁，用于保护 watchOps、room 和 rooms 的并发访问
+	ack      *ackWindow           //*未确认消息窗口,未开启消息确认时为nil
+	queue    pushQueue            //*待发送的服务端消息
+	policy   *pushPolicy          //*推送缓冲的策略,为nil时使用默认策略
+	wakeup   chan struct{}        //*推送队列有新消息时唤醒发送协程,容量为1
+	closing  bool                 //*已收到ProtoFinish,发完队列中的消息后退出,只在发送协程中使用
+	conn     io.Closer            //*底层连接,由Serve*在放入bucket前设置
 }
 
 // *新建一个通道
@@ -30,7 +36,9 @@ func NewChannel(cli, svr int) *Channel {
 	c := new(Channel)
 	c.CliProto.Init(cli)
 	c.signal = make(chan *protocol.Proto, svr)
+	c.wakeup = make(chan struct{}, 1)
 	c.watchOps = make(map[int32]struct{})
+	c.rooms = make(map[string]*Room)
 	return c
 }
 
@@ -65,27 +73,131 @@ func (c *Channel) NeedPush(op int32) bool {
 	return false
 }
 
-// *用于将消息推送到 Channel 的信号通道 (signal)
+// *返回连接加入的所有房间
+func (c *Channel) Rooms() (rooms []*Room) {
+	c.mutex.RLock()
+	for _, room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	c.mutex.RUnlock()
+	return
+}
+
+// *返回连接加入的房间数
+func (c *Channel) RoomNum() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.rooms)
+}
+
+// *返回rid对应的已加入的房间,未加入时返回nil
+func (c *Channel) InRoom(rid string) *Room {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.rooms[rid]
+}
+
+// *返回主房间,没有主房间时返回nil
+func (c *Channel) Room() *Room {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.room
+}
+
+// *设置主房间
+func (c *Channel) setRoom(room *Room) {
+	c.mutex.Lock()
+	c.room = room
+	c.mutex.Unlock()
+}
+
+// *记录加入的房间
+func (c *Channel) addRoom(room *Room) {
+	c.mutex.Lock()
+	c.rooms[room.ID] = room
+	c.mutex.Unlock()
+}
+
+// *删除加入的房间,返回被删除的房间,离开的是主房间时同时清空主房间
+func (c *Channel) delRoom(rid string) (room *Room) {
+	c.mutex.Lock()
+	if room = c.rooms[rid]; room != nil {
+		delete(c.rooms, rid)
+		if c.room == room {
+			c.room = nil
+		}
+	}
+	c.mutex.Unlock()
+	return
+}
+
+// *用于将消息放入 Channel 的推送队列,待发送的消息超过上限时按慢消费者策略处理
 func (c *Channel) Push(p *protocol.Proto) (err error) {
+	policy := c.policy
+	if policy == nil {
+		policy = defaultPushPolicy
+	}
+	if err = c.queue.push(policy, p); err != nil {
+		logger.Error("channel推送队列已满",zap.Int64("mid(用户id)",c.Mid),zap.Any("p(消息)",p),zap.Error(err))
+		if err != errors.ErrSlowConsumer {
+			return
+		}
+	} else {
+		metricPushes.Inc()
+		logger.Debug("channel推送队列写入消息成功",zap.Int64("mid(用户id)",c.Mid),zap.Any("p(消息)",p))
+	}
+	c.notify()
+	return
+}
+
+// *唤醒发送协程,唤醒通道中已有通知时不重复发送;发送协程每次等待前都会检查推送队列,不会漏掉消息
+func (c *Channel) notify() {
 	select {
-	case c.signal <- p:
-		logger.Info("channel信号通道写入消息成功",zap.Int64("mid(用户id)",c.Mid),zap.Any("p(消息)",p))
+	case c.wakeup <- struct{}{}:
 	default:
-		logger.Error("channel信号通道已满",zap.Int64("mid(用户id)",c.Mid),zap.Any("p(消息)",p))
-		err = errors.ErrSignalFullMsgDropped
 	}
-	return
 }
 
-// *用于从信号通道 (signal) 中读取消息
+// *用于读取下一条要发送的消息: 信号通道中的消息优先,其次是推送队列中的消息;
+// *收到ProtoFinish时先发完队列中的消息,按disconnect策略断开时发完断开通知后返回ProtoFinish
 func (c *Channel) Ready() *protocol.Proto {
-	logger.Info("channel信号通道读取消息",zap.Int64("mid(用户id)",c.Mid))
-	return <-c.signal
+	logger.Debug("channel信号通道读取消息",zap.Int64("mid(用户id)",c.Mid))
+	for {
+		if c.closing {
+			if p, _ := c.queue.pop(); p != nil {
+				return p
+			}
+			return protocol.ProtoFinish
+		}
+		select {
+		case p := <-c.signal:
+			if p != protocol.ProtoFinish {
+				return p
+			}
+			c.closing = true
+			continue
+		default:
+		}
+		if p, more := c.queue.pop(); p != nil {
+			return p
+		} else if more {
+			//*队列已空且已按disconnect策略断开
+			return protocol.ProtoFinish
+		}
+		select {
+		case p := <-c.signal:
+			if p != protocol.ProtoFinish {
+				return p
+			}
+			c.closing = true
+		case <-c.wakeup:
+		}
+	}
 }
 
 // *用于向信号通道 (signal) 发送 ProtoReady 信号，通知 Channel 有新的消息需要处理
 func (c *Channel) Signal() {
-	logger.Info("channel信号通道发送ProtoReady信号",zap.Int64("mid(用户id)",c.Mid))
+	logger.Debug("channel信号通道发送ProtoReady信号",zap.Int64("mid(用户id)",c.Mid))
 	c.signal <- protocol.ProtoReady
 }
 
@@ -94,3 +206,20 @@ func (c *Channel) Close() {
 	logger.Info("channel信号通道发送ProtoFinish信号",zap.Int64("mid(用户id)",c.Mid))
 	c.signal <- protocol.ProtoFinish
 }
+
+// *不阻塞地关闭通道: 信号通道有空位时发送ProtoFinish,让发送协程发完队列中的消息后退出;
+// *信号通道已满说明发送协程卡住了,直接关闭底层连接,读写协程随之出错退出
+func (c *Channel) Shutdown() {
+	select {
+	case c.signal <- protocol.ProtoFinish:
+	default:
+		c.closeConn()
+	}
+}
+
+// *直接关闭底层连接,排队中的消息会丢失
+func (c *Channel) closeConn() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
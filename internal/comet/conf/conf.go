@@ -11,6 +11,7 @@ import (
 	//*用于解析 TOML 配置文件
 	"github.com/BurntSushi/toml"
 	xtime "github.com/gyy0727/mygoim/pkg/time"
+	"go.uber.org/zap/zapcore"
 )
 
 var (
@@ -51,6 +52,36 @@ func Init() (err error) {
 	return
 }
 
+// *重新解析配置文件并校验,用于收到SIGHUP时热加载
+func Load() (c *Config, err error) {
+	c = Default()
+	if _, err = toml.DecodeFile(confPath, &c); err != nil {
+		return
+	}
+	err = c.Validate()
+	return
+}
+
+// *校验可以热加载的配置
+func (c *Config) Validate() error {
+	if c.Protocol == nil || c.Protocol.HandshakeTimeout <= 0 {
+		return fmt.Errorf("invalid Protocol.HandshakeTimeout")
+	}
+	if c.Env == nil || c.Env.Weight < 0 {
+		return fmt.Errorf("invalid Env.Weight")
+	}
+	if c.Whitelist == nil {
+		return fmt.Errorf("invalid Whitelist")
+	}
+	if c.Log == nil {
+		return fmt.Errorf("invalid Log")
+	}
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("invalid Log.Level: %v", err)
+	}
+	return nil
+}
+
 func Default() *Config {
 	return &Config{
 		Debug: debug,
@@ -98,6 +129,42 @@ func Default() *Config {
 			SvrProto:         10,
 			HandshakeTimeout: xtime.Duration(time.Second * 5),
 		},
+		Ack: &Ack{
+			Open:    false,
+			Window:  32,
+			Timeout: xtime.Duration(time.Second * 5),
+			Retry:   3,
+		},
+		History: &History{
+			Open:  false,
+			Limit: 0,
+		},
+		Room: &Room{
+			Authorize: true,
+			Max:       1,
+		},
+		Metrics: &Metrics{
+			Open: false,
+			Addr: ":3110",
+			Path: "/metrics",
+		},
+		Admin: &Admin{
+			Open: false,
+			Addr: "127.0.0.1:3111",
+		},
+		Log: &Log{
+			Level: "debug",
+		},
+		Drain: &Drain{
+			Wave:     1000,
+			Interval: xtime.Duration(time.Second),
+			Timeout:  xtime.Duration(time.Second * 30),
+		},
+		Backoff: &Backoff{MaxDelay: 300, BaseDelay: 3, Factor: 1.8, Jitter: 1.3},
+		Push: &Push{
+			MaxBytes: 256 * 1024,
+			Policy:   "drop_newest",
+		},
 		Bucket: &Bucket{
 			Size:          32,
 			Channel:       1024,
@@ -120,6 +187,15 @@ type Config struct {
 	RPCClient *RPCClient  // *RPC客户端配置
 	RPCServer *RPCServer  // *RPC服务端配置
 	Whitelist *Whitelist  // *白名单配置
+	Ack       *Ack        // *消息确认配置
+	History   *History    // *房间历史消息配置
+	Room      *Room       // *房间配置
+	Metrics   *Metrics    // *监控配置
+	Admin     *Admin      // *管理接口配置
+	Log       *Log        // *日志配置
+	Drain     *Drain      // *优雅关闭配置
+	Backoff   *Backoff    // *客户端重连的退避策略
+	Push      *Push       // *推送缓冲和慢消费者策略
 }
 
 // *Etcd服务发现配置
@@ -207,6 +283,75 @@ type Whitelist struct {
 	WhiteLog  string  // *白名单日志文件路径
 }
 
+// *消息确认配置
+type Ack struct {
+	Open    bool           // *是否开启消息确认
+	Ops     []int32        // *需要客户端确认的操作码
+	Window  int            // *每个连接最多未确认的消息数
+	Timeout xtime.Duration // *未确认消息的重传间隔
+	Retry   int            // *最大重传次数,超过后断开连接
+}
+
+// *房间历史消息配置
+type History struct {
+	Open  bool // *加入房间时是否推送房间历史消息
+	Limit int  // *加入房间时推送的条数,0表示由logic按房间类型决定
+}
+
+// *房间配置
+type Room struct {
+	Authorize bool // *切换和加入房间前是否由logic鉴权,默认开启
+	Max       int  // *每个连接最多同时加入的房间数,包含主房间,<=0表示不限制
+}
+
+// *监控配置
+type Metrics struct {
+	Open bool   // *是否开启prometheus监控
+	Addr string // *监控http服务的监听地址
+	Path string // *监控数据的路径
+}
+
+// *日志配置
+type Log struct {
+	Level string // *日志级别: debug、info、warn、error
+}
+
+// *优雅关闭配置
+type Drain struct {
+	Wave     int            // *每一批关闭的连接数
+	Interval xtime.Duration // *两批之间的间隔
+	Timeout  xtime.Duration // *等待所有连接关闭并上报logic的最长时间
+}
+
+// *客户端重连的退避策略,单位秒,与logic的Backoff一致
+type Backoff struct {
+	MaxDelay  int32   // *最大延迟
+	BaseDelay int32   // *基础延迟
+	Factor    float32 // *每一批的延迟因子
+	Jitter    float32 // *抖动,在[延迟,延迟*Jitter]之间随机
+}
+
+// *推送缓冲配置,连接待发送的消息超过MaxBytes时按操作码所属分类的策略处理
+type Push struct {
+	MaxBytes int          // *每个连接待发送消息的最大字节数,包含协议头
+	Policy   string       // *不属于任何分类的操作码的策略
+	Classes  []*PushClass // *按操作码分类的策略
+}
+
+// *一类操作码的慢消费者策略
+type PushClass struct {
+	Name   string  // *分类名称,用于监控
+	Ops    []int32 // *属于该分类的操作码,job打包的OpRaw按第一条消息的操作码分类
+	Policy string  // *drop_newest丢弃新消息、drop_oldest丢弃同类最旧的消息、coalesce用新消息替换同操作码的旧消息、disconnect断开连接
+}
+
+// *管理接口配置
+type Admin struct {
+	Open  bool   // *是否开启管理http服务
+	Addr  string // *监听地址,建议只绑定内网或本机地址
+	Token string // *访问令牌,通过请求头X-Admin-Token传入,开启时必须配置
+}
+
 // *=============================================
 func (c *Config) String() string {
 	return fmt.Sprintf(`Config{
@@ -219,9 +364,18 @@ func (c *Config) String() string {
     Bucket: %s,
     RPCClient: %s,
     RPCServer: %s,
-    Whitelist: %s
+    Whitelist: %s,
+    Ack: %s,
+    History: %s,
+    Room: %s,
+    Metrics: %s,
+    Admin: %s,
+    Log: %s,
+    Drain: %s,
+    Backoff: %s,
+    Push: %s
 }`,
-		c.Debug, c.Env.String(), c.Etcd.String(), c.TCP.String(), c.Websocket.String(), c.Protocol.String(), c.Bucket.String(), c.RPCClient.String(), c.RPCServer.String(), c.Whitelist.String())
+		c.Debug, c.Env.String(), c.Etcd.String(), c.TCP.String(), c.Websocket.String(), c.Protocol.String(), c.Bucket.String(), c.RPCClient.String(), c.RPCServer.String(), c.Whitelist.String(), c.Ack.String(), c.History.String(), c.Room.String(), c.Metrics.String(), c.Admin.String(), c.Log.String(), c.Drain.String(), c.Backoff.String(), c.Push.String())
 }
 
 func (e *EtcdConfig) String() string {
@@ -326,3 +480,91 @@ func (w *Whitelist) String() string {
 }`,
 		w.Whitelist, w.WhiteLog)
 }
+
+func (a *Ack) String() string {
+	return fmt.Sprintf(`Ack{
+    Open: %v,
+    Ops: %v,
+    Window: %d,
+    Timeout: %v,
+    Retry: %d
+}`,
+		a.Open, a.Ops, a.Window, a.Timeout, a.Retry)
+}
+
+func (h *History) String() string {
+	return fmt.Sprintf(`History{
+    Open: %v,
+    Limit: %d
+}`,
+		h.Open, h.Limit)
+}
+
+func (r *Room) String() string {
+	return fmt.Sprintf(`Room{
+    Authorize: %v,
+    Max: %d
+}`,
+		r.Authorize, r.Max)
+}
+
+func (m *Metrics) String() string {
+	return fmt.Sprintf(`Metrics{
+    Open: %v,
+    Addr: %s,
+    Path: %s
+}`,
+		m.Open, m.Addr, m.Path)
+}
+
+func (a *Admin) String() string {
+	token := ""
+	if a.Token != "" {
+		token = "******"
+	}
+	return fmt.Sprintf(`Admin{
+    Open: %v,
+    Addr: %s,
+    Token: %s
+}`,
+		a.Open, a.Addr, token)
+}
+
+func (l *Log) String() string {
+	return fmt.Sprintf(`Log{
+    Level: %s
+}`,
+		l.Level)
+}
+
+func (d *Drain) String() string {
+	return fmt.Sprintf(`Drain{
+    Wave: %d,
+    Interval: %v,
+    Timeout: %v
+}`,
+		d.Wave, d.Interval, d.Timeout)
+}
+
+func (b *Backoff) String() string {
+	return fmt.Sprintf(`Backoff{
+    MaxDelay: %d,
+    BaseDelay: %d,
+    Factor: %v,
+    Jitter: %v
+}`,
+		b.MaxDelay, b.BaseDelay, b.Factor, b.Jitter)
+}
+
+func (p *Push) String() string {
+	classes := make([]string, 0, len(p.Classes))
+	for _, c := range p.Classes {
+		classes = append(classes, fmt.Sprintf("%s%v:%s", c.Name, c.Ops, c.Policy))
+	}
+	return fmt.Sprintf(`Push{
+    MaxBytes: %d,
+    Policy: %s,
+    Classes: %v
+}`,
+		p.MaxBytes, p.Policy, classes)
+}
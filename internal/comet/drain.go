@@ -0,0 +1,151 @@
+package comet
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"go.uber.org/zap"
+)
+
+const (
+	// *没有可关闭的连接时,等待剩余连接上报logic的轮询间隔
+	drainPoll = time.Millisecond * 100
+	// *一批连接中每关闭多少个检查一次截止时间
+	drainCheck = 64
+)
+
+// *记录监听器,关闭时统一停止接受新连接
+func (s *Server) addListener(lis net.Listener) {
+	s.lisMutex.Lock()
+	s.listeners = append(s.listeners, lis)
+	s.lisMutex.Unlock()
+}
+
+// *是否正在优雅关闭
+func (s *Server) Draining() bool {
+	return atomic.LoadInt32(&s.draining) == 1
+}
+
+// *优雅关闭server: 停止接受新连接,分批通知客户端稍后重连并关闭连接,
+// *每个连接退出时向logic上报断开,等待全部上报完成后返回,超时时强制关闭剩余连接后返回
+func (s *Server) Close() (err error) {
+	if !atomic.CompareAndSwapInt32(&s.draining, 0, 1) {
+		return
+	}
+	s.lisMutex.Lock()
+	for _, lis := range s.listeners {
+		_ = lis.Close()
+	}
+	s.lisMutex.Unlock()
+	var (
+		c        = s.c.Drain
+		deadline = time.Now().Add(time.Duration(c.Timeout))
+		drained  = make(map[*Channel]struct{})
+		wave     int
+	)
+	logger.Info("comet draining", zap.Int64("serving", atomic.LoadInt64(&s.serving)))
+	for {
+		chs := s.drainWave(drained, c.Wave)
+		if len(chs) == 0 && atomic.LoadInt64(&s.serving) == 0 {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			s.drainTimeout(wave)
+			return
+		}
+		if len(chs) == 0 {
+			//*剩下的连接已通知关闭或仍在握手中,等待它们退出
+			sleepUntil(drainPoll, deadline)
+			continue
+		}
+		count := 0
+		for _, ch := range chs {
+			//*一批连接很多时也不能超过截止时间,剩下的在超时时强制关闭
+			if count%drainCheck == 0 && !time.Now().Before(deadline) {
+				break
+			}
+			drained[ch] = struct{}{}
+			s.drainChannel(ch, reconnectDelay(s.c.Backoff, wave))
+			count++
+		}
+		logger.Info("comet drain wave",
+			zap.Int("wave", wave),
+			zap.Int("count", count),
+		)
+		wave++
+		sleepUntil(time.Duration(c.Interval), deadline)
+	}
+	logger.Info("comet drained", zap.Int("waves", wave), zap.Int("conns", len(drained)))
+	return
+}
+
+// *超时后直接关闭所有剩余连接的底层连接,读协程出错退出时仍会向logic上报断开
+func (s *Server) drainTimeout(wave int) {
+	var closed int
+	for _, b := range s.buckets {
+		for _, ch := range b.Channels() {
+			ch.closeConn()
+			closed++
+		}
+	}
+	logger.Error("comet drain timeout",
+		zap.Int64("serving", atomic.LoadInt64(&s.serving)),
+		zap.Int("wave", wave),
+		zap.Int("closed", closed),
+	)
+}
+
+// *睡眠d,但不超过deadline
+func sleepUntil(d time.Duration, deadline time.Time) {
+	if left := time.Until(deadline); left < d {
+		d = left
+	}
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// *取出下一批还未通知关闭的连接,limit<=0时不限制数量
+func (s *Server) drainWave(drained map[*Channel]struct{}, limit int) (chs []*Channel) {
+	for _, b := range s.buckets {
+		for _, ch := range b.Channels() {
+			if _, ok := drained[ch]; ok {
+				continue
+			}
+			chs = append(chs, ch)
+			if limit > 0 && len(chs) >= limit {
+				return
+			}
+		}
+	}
+	return
+}
+
+// *发送带重连延迟的OpDisconnectReply后关闭连接,连接退出时由读协程向logic上报断开
+func (s *Server) drainChannel(ch *Channel, delay time.Duration) {
+	body, _ := json.Marshal(map[string]int64{
+		"reason":    int64(protocol.KickReasonDrain),
+		"reconnect": int64(delay / time.Millisecond),
+	})
+	//*发送协程卡住时直接断开连接,客户端收不到重连延迟,按自身的退避策略重连
+	_ = ch.Push(&protocol.Proto{Ver: 1, Op: protocol.OpDisconnectReply, Body: body})
+	ch.Shutdown()
+}
+
+// *第wave批连接的重连延迟: BaseDelay*Factor^wave,再在[延迟,延迟*Jitter]之间随机,不超过MaxDelay
+func reconnectDelay(c *conf.Backoff, wave int) time.Duration {
+	d := float64(c.BaseDelay) * math.Pow(float64(c.Factor), float64(wave))
+	if c.Jitter > 1 {
+		d *= 1 + rand.Float64()*float64(c.Jitter-1)
+	}
+	if max := float64(c.MaxDelay); d > max {
+		d = max
+	}
+	return time.Duration(d * float64(time.Second))
+}
@@ -0,0 +1,129 @@
+package comet
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	xtime "github.com/gyy0727/mygoim/pkg/time"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	c := &conf.Backoff{MaxDelay: 300, BaseDelay: 3, Factor: 2, Jitter: 1.5}
+	for wave, base := range []time.Duration{3, 6, 12, 24} {
+		d := reconnectDelay(c, wave)
+		if min, max := base*time.Second, base*time.Second*3/2; d < min || d > max {
+			t.Fatalf("wave %d delay %v not in [%v,%v]", wave, d, min, max)
+		}
+	}
+	// 超过最大延迟时取MaxDelay
+	if d := reconnectDelay(c, 20); d != 300*time.Second {
+		t.Fatalf("delay %v want 300s", d)
+	}
+	// 不抖动时延迟是确定的
+	c.Jitter = 0
+	if d := reconnectDelay(c, 1); d != 6*time.Second {
+		t.Fatalf("delay %v want 6s", d)
+	}
+}
+
+type drainConn struct {
+	closed int32
+}
+
+func (c *drainConn) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+func newDrainServer(d *conf.Drain) *Server {
+	c := conf.Default()
+	c.Drain = d
+	c.Backoff = &conf.Backoff{MaxDelay: 300, BaseDelay: 1, Factor: 2}
+	c.Bucket.Size = 2
+	c.Bucket.RoutineAmount = 1
+	s := &Server{c: c, buckets: make([]*Bucket, c.Bucket.Size)}
+	for i := range s.buckets {
+		s.buckets[i] = NewBucket(c.Bucket)
+	}
+	return s
+}
+
+func newDrainChannel(s *Server, key string, svr int) (*Channel, *drainConn) {
+	ch := NewChannel(1, svr)
+	ch.Key = key
+	conn := &drainConn{}
+	ch.conn = conn
+	_ = s.buckets[len(key)%len(s.buckets)].Put("", ch)
+	return ch, conn
+}
+
+func TestServerCloseWaves(t *testing.T) {
+	s := newDrainServer(&conf.Drain{Wave: 2, Interval: xtime.Duration(time.Millisecond * 20), Timeout: xtime.Duration(time.Second * 5)})
+	var (
+		mutex  sync.Mutex
+		delays = make(map[int64]int)
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < 5; i++ {
+		ch, _ := newDrainChannel(s, fmt.Sprintf("key-%d", i), 4)
+		b := s.buckets[len(ch.Key)%len(s.buckets)]
+		wg.Add(1)
+		// 模拟发送协程: 记录重连延迟,收到ProtoFinish后离开bucket
+		go func() {
+			defer wg.Done()
+			for {
+				p := ch.Ready()
+				if p == protocol.ProtoFinish {
+					b.Del(ch)
+					return
+				}
+				var body map[string]int64
+				_ = json.Unmarshal(p.Body, &body)
+				mutex.Lock()
+				delays[body["reconnect"]]++
+				mutex.Unlock()
+			}
+		}()
+	}
+	start := time.Now()
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	// 5个连接分3批,每批之间间隔Interval
+	if d := time.Since(start); d < time.Millisecond*40 {
+		t.Fatalf("close took %v, want >= 40ms", d)
+	}
+	want := map[int64]int{1000: 2, 2000: 2, 4000: 1}
+	if !reflect.DeepEqual(delays, want) {
+		t.Fatalf("delays %v want %v", delays, want)
+	}
+	if !s.Draining() {
+		t.Fatal("server not draining")
+	}
+}
+
+func TestServerCloseTimeout(t *testing.T) {
+	s := newDrainServer(&conf.Drain{Wave: 10, Interval: xtime.Duration(time.Millisecond * 10), Timeout: xtime.Duration(time.Millisecond * 50)})
+	s.serving = 1
+	// 发送协程卡住,信号通道还有空位: 先发ProtoFinish,超时后强制关闭连接
+	_, stuck := newDrainChannel(s, "stuck", 2)
+	// 信号通道已满: 不等待,直接关闭连接
+	full, fullConn := newDrainChannel(s, "full", 1)
+	full.signal <- protocol.ProtoReady
+	start := time.Now()
+	_ = s.Close()
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("close took %v, want about 50ms", d)
+	}
+	if atomic.LoadInt32(&stuck.closed) != 1 || atomic.LoadInt32(&fullConn.closed) != 1 {
+		t.Fatal("conn not closed after drain timeout")
+	}
+}
@@ -34,6 +34,18 @@ var (
 	ErrMPushMsgsArg         = errors.New("rpc mpushmsgs arg error")
 	//*信号通道已满,丢弃消息 
 	ErrSignalFullMsgDropped = errors.New("signal channel full, msg dropped")
+	//*未确认消息窗口已满
+	ErrAckWindowFull = errors.New("ack window full")
+	//*推送缓冲已满,按策略断开慢消费者
+	ErrSlowConsumer = errors.New("slow consumer disconnected")
+	//*踢人参数错误
+	ErrKickArg = errors.New("rpc kick arg error")
+	//*房间成员参数错误
+	ErrRoomMembersArg = errors.New("rpc room members arg error")
+	//*白名单参数错误
+	ErrWhitelistArg = errors.New("rpc whitelist arg error")
+	//*转移连接参数错误
+	ErrShedArg = errors.New("rpc shed arg error")
 	//!bucket
 	//*广播参数错误 
 	ErrBroadCastArg     = errors.New("rpc broadcast arg error")
@@ -42,7 +54,12 @@ var (
 	//!room
 	//*房间已丢弃 
 	ErrRoomDroped = errors.New("room droped")
+	//*连接加入的房间数已达上限
+	ErrRoomFull = errors.New("channel rooms full")
 	//!rpc
 	//*logic rpc不可用 
 	ErrLogic = errors.New("logic rpc is not available")
+	//!admin
+	//*开启管理接口时必须配置访问令牌
+	ErrAdminToken = errors.New("admin token required")
 )
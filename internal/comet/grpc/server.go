@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"net"
+	"sort"
 	"time"
 
 	"github.com/gyy0727/mygoim/internal/comet"
@@ -46,6 +47,7 @@ func (s *server) PushMsg(ctx context.Context, req *pb.PushMsgReq) (reply *pb.Pus
 	if len(req.Keys) == 0 || req.Proto == nil {
 		return nil, errors.ErrPushMsgArg
 	}
+	//*某个连接推送失败时继续推送其余的连接,返回第一个错误
 	for _, key := range req.Keys {
 		bucket := s.srv.Bucket(key)
 		if bucket == nil {
@@ -55,11 +57,20 @@ func (s *server) PushMsg(ctx context.Context, req *pb.PushMsgReq) (reply *pb.Pus
 			if !channel.NeedPush(req.ProtoOp) {
 				continue
 			}
-			if err = channel.Push(req.Proto); err != nil {
-				return
+			var e error
+			if s.srv.NeedAck(req.ProtoOp) {
+				e = s.srv.PushAck(channel, req.Proto)
+			} else {
+				e = channel.Push(req.Proto)
+			}
+			if e != nil && err == nil {
+				err = e
 			}
 		}
 	}
+	if err != nil {
+		return
+	}
 	return &pb.PushMsgReply{}, nil
 }
 
@@ -104,3 +115,91 @@ func (s *server) Rooms(ctx context.Context, req *pb.RoomsReq) (*pb.RoomsReply, e
 	}
 	return &pb.RoomsReply{Rooms: roomIds}, nil
 }
+
+// Kick close the conns of specified keys.
+func (s *server) Kick(ctx context.Context, req *pb.KickReq) (*pb.KickReply, error) {
+	if len(req.Keys) == 0 {
+		return nil, errors.ErrKickArg
+	}
+	var count int32
+	for _, key := range req.Keys {
+		if s.srv.Kick(key, req.Reason) {
+			count++
+		}
+	}
+	return &pb.KickReply{Count: count}, nil
+}
+
+// RoomMembers list the conns in the room, sorted by key.
+func (s *server) RoomMembers(ctx context.Context, req *pb.RoomMembersReq) (*pb.RoomMembersReply, error) {
+	if req.RoomID == "" || req.Offset < 0 || req.Limit < 0 {
+		return nil, errors.ErrRoomMembersArg
+	}
+	var members []*pb.RoomMember
+	for _, bucket := range s.srv.Buckets() {
+		room := bucket.Room(req.RoomID)
+		if room == nil {
+			continue
+		}
+		for _, ch := range room.Channels() {
+			members = append(members, &pb.RoomMember{Mid: ch.Mid, Key: ch.Key})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Key < members[j].Key })
+	reply := &pb.RoomMembersReply{Total: int32(len(members))}
+	if int(req.Offset) >= len(members) {
+		return reply, nil
+	}
+	members = members[req.Offset:]
+	if req.Limit > 0 && int(req.Limit) < len(members) {
+		members = members[:req.Limit]
+	}
+	reply.Members = members
+	return reply, nil
+}
+
+// Stats get conn, room and runtime stats of the server.
+func (s *server) Stats(ctx context.Context, req *pb.StatsReq) (*pb.StatsReply, error) {
+	st := s.srv.Stats()
+	reply := &pb.StatsReply{
+		Buckets:       make([]*pb.BucketStats, 0, len(st.Buckets)),
+		Channels:      int64(st.Channels),
+		Rooms:         int64(st.Rooms),
+		Ips:           int64(st.IPs),
+		SignalDropped: st.SignalDropped,
+		Goroutines:    int32(st.Goroutines),
+		HeapAlloc:     st.HeapAlloc,
+		HeapInuse:     st.HeapInuse,
+		Sys:           st.Sys,
+		NumGC:         st.NumGC,
+		StartTime:     st.StartTime,
+	}
+	for _, b := range st.Buckets {
+		reply.Buckets = append(reply.Buckets, &pb.BucketStats{Channels: int32(b.Channels), Rooms: int32(b.Rooms), Ips: int32(b.IPs)})
+	}
+	return reply, nil
+}
+
+// AddWhitelist add mids or key prefixes to the whitelist, ttl in seconds.
+func (s *server) AddWhitelist(ctx context.Context, req *pb.WhitelistReq) (*pb.WhitelistReply, error) {
+	if err := s.srv.AddWhitelist(req.Mids, req.Prefixes, int(req.Sample), time.Duration(req.Ttl)*time.Second); err != nil {
+		return nil, err
+	}
+	return &pb.WhitelistReply{}, nil
+}
+
+// DelWhitelist remove mids or key prefixes from the whitelist.
+func (s *server) DelWhitelist(ctx context.Context, req *pb.WhitelistReq) (*pb.WhitelistReply, error) {
+	if err := s.srv.DelWhitelist(req.Mids, req.Prefixes); err != nil {
+		return nil, err
+	}
+	return &pb.WhitelistReply{}, nil
+}
+
+// Shed redirect some conns to another server for rebalancing.
+func (s *server) Shed(ctx context.Context, req *pb.ShedReq) (*pb.ShedReply, error) {
+	if req.Count <= 0 || req.Server == "" {
+		return nil, errors.ErrShedArg
+	}
+	return &pb.ShedReply{Count: int32(s.srv.Shed(int(req.Count), req.Server, req.Addrs))}, nil
+}
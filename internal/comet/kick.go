@@ -0,0 +1,30 @@
+package comet
+
+import (
+	"encoding/json"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"go.uber.org/zap"
+)
+
+// *踢掉key对应的连接,先发送带原因码的OpDisconnectReply再关闭,连接不在本机时返回false
+func (s *Server) Kick(key string, reason int32) bool {
+	bucket := s.Bucket(key)
+	if bucket == nil {
+		return false
+	}
+	ch := bucket.Channel(key)
+	if ch == nil {
+		return false
+	}
+	body, _ := json.Marshal(map[string]int32{"reason": reason})
+	//*原因码先入队,Shutdown不阻塞;发送协程卡住时直接断开连接,此时客户端收不到原因码
+	_ = ch.Push(&protocol.Proto{Ver: 1, Op: protocol.OpDisconnectReply, Body: body})
+	ch.Shutdown()
+	logger.Info("kick channel",
+		zap.String("key", key),
+		zap.Int64("mid", ch.Mid),
+		zap.Int32("reason", reason),
+	)
+	return true
+}
@@ -8,7 +8,10 @@ import (
 	_ "github.com/gyy0727/mygoim/internal/comet/conf"
 )
 
-var logger *zap.Logger
+var (
+	logger   *zap.Logger
+	logLevel = zap.NewAtomicLevelAt(zap.DebugLevel) //*日志级别,可以运行时修改
+)
 
 func init() {
 	//*Zap 基础配置
@@ -45,10 +48,15 @@ func init() {
 	core := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(encoderConfig), //*编码器（保持与之前一致）
 		combinedSyncer,                           //*输出位置
-		zap.DebugLevel,                           //*日志级别
+		logLevel,                                 //*日志级别
 	)
 
 	//*创建 Logger
 	logger = zap.New(core, zap.AddCaller())
 	zap.ReplaceGlobals(logger) //*替换全局 Logger
 }
+
+// *修改日志级别: debug、info、warn、error
+func SetLogLevel(level string) error {
+	return logLevel.UnmarshalText([]byte(level))
+}
@@ -0,0 +1,222 @@
+package comet
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const (
+	metricNamespace = "goim"
+	metricSubsystem = "comet"
+	// *按操作码统计时的操作码上限,超过的统一记为other,避免客户端随意发送的操作码撑大标签
+	maxMetricOp = 256
+
+	protoTCP       = "tcp"
+	protoWebsocket = "websocket"
+)
+
+var (
+	// *建立的连接数
+	metricConnections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "connections_total",
+		Help:      "Accepted client connections.",
+	}, []string{"proto"})
+	// *握手次数,result为ok或failed
+	metricHandshakes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "handshakes_total",
+		Help:      "Client handshakes by result.",
+	}, []string{"proto", "result"})
+	// *握手耗时
+	metricHandshakeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "handshake_duration_seconds",
+		Help:      "Client handshake latency, including the logic Connect call.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"proto"})
+	// *收到的心跳数
+	metricHeartbeats = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "heartbeats_total",
+		Help:      "Client heartbeats received.",
+	}, []string{"proto"})
+	// *按操作码统计读到的消息数
+	metricReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "proto_reads_total",
+		Help:      "Protos read from clients by op.",
+	}, []string{"proto", "op"})
+	// *按操作码统计写出的消息数
+	metricWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "proto_writes_total",
+		Help:      "Protos written to clients by op.",
+	}, []string{"proto", "op"})
+	// *刷新写缓冲区的耗时
+	metricFlushDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "flush_duration_seconds",
+		Help:      "Write buffer flush latency in the dispatch goroutine.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	}, []string{"proto"})
+	// *放入信号通道的推送数
+	metricPushes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "pushes_total",
+		Help:      "Protos pushed into channel signal queues.",
+	})
+	// *信号通道已满被丢弃的消息数
+	metricSignalDropped = prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "signal_dropped_total",
+		Help:      "Protos dropped because the channel push buffer was full (ErrSignalFullMsgDropped).",
+	}, func() float64 { return float64(SignalDropped()) })
+	// *客户端消息环形缓冲区已满的次数
+	metricRingFull = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "ring_full_total",
+		Help:      "Client proto ring full occurrences (ErrRingFull).",
+	})
+	// *推送缓冲超过上限时按分类和处理动作统计的次数
+	metricSlowConsumer = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "slow_consumer_total",
+		Help:      "Slow consumer policy actions by op class and action.",
+	}, []string{"class", "action"})
+	// *按方法统计调用logic失败的次数
+	metricLogicErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "logic_rpc_errors_total",
+		Help:      "Failed logic RPC calls by method.",
+	}, []string{"method"})
+
+	// *预先格式化的操作码标签
+	metricOps [maxMetricOp]string
+)
+
+func init() {
+	for i := range metricOps {
+		metricOps[i] = strconv.Itoa(i)
+	}
+	prometheus.MustRegister(
+		metricConnections,
+		metricHandshakes,
+		metricHandshakeDuration,
+		metricHeartbeats,
+		metricReads,
+		metricWrites,
+		metricFlushDuration,
+		metricPushes,
+		metricSignalDropped,
+		metricRingFull,
+		metricSlowConsumer,
+		metricLogicErrors,
+	)
+}
+
+// *操作码对应的标签
+func metricOp(op int32) string {
+	if op < 0 || op >= maxMetricOp {
+		return "other"
+	}
+	return metricOps[op]
+}
+
+// *握手结果对应的标签
+func metricResult(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "ok"
+}
+
+// *统计调用logic失败的次数,method只保留方法名
+func metricLogicInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		metricLogicErrors.WithLabelValues(path.Base(method)).Inc()
+	}
+	return err
+}
+
+// *抓取时按bucket统计连接数和房间数
+type bucketCollector struct {
+	s        *Server
+	channels *prometheus.Desc
+	rooms    *prometheus.Desc
+}
+
+func newBucketCollector(s *Server) *bucketCollector {
+	return &bucketCollector{
+		s: s,
+		channels: prometheus.NewDesc(prometheus.BuildFQName(metricNamespace, metricSubsystem, "bucket_channels"),
+			"Channels in the bucket.", []string{"bucket"}, nil),
+		rooms: prometheus.NewDesc(prometheus.BuildFQName(metricNamespace, metricSubsystem, "bucket_rooms"),
+			"Rooms with online channels in the bucket.", []string{"bucket"}, nil),
+	}
+}
+
+func (c *bucketCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.channels
+	ch <- c.rooms
+}
+
+func (c *bucketCollector) Collect(ch chan<- prometheus.Metric) {
+	for i, b := range c.s.Buckets() {
+		idx := strconv.Itoa(i)
+		ch <- prometheus.MustNewConstMetric(c.channels, prometheus.GaugeValue, float64(b.ChannelCount()), idx)
+		ch <- prometheus.MustNewConstMetric(c.rooms, prometheus.GaugeValue, float64(len(b.Rooms())), idx)
+	}
+}
+
+// *开启监控时注册bucket统计并启动/metrics的http服务
+func InitMetrics(s *Server, c *conf.Metrics) (err error) {
+	if !c.Open {
+		return
+	}
+	if err = prometheus.Register(newBucketCollector(s)); err != nil {
+		return
+	}
+	lis, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		logger.Error("Failed to listen on metrics address",
+			zap.String("addr", c.Addr),
+			zap.Error(err),
+		)
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle(c.Path, promhttp.Handler())
+	logger.Info("Start metrics listen",
+		zap.String("addr", c.Addr),
+		zap.String("path", c.Path),
+	)
+	go func() {
+		if err := http.Serve(lis, mux); err != nil {
+			logger.Error("metrics server exit", zap.Error(err))
+		}
+	}()
+	return
+}
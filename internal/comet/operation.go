@@ -2,9 +2,13 @@ package comet
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"time"
 	"github.com/gyy0727/mygoim/api/logic"
 	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+	"github.com/gyy0727/mygoim/pkg/bytes"
 	"github.com/gyy0727/mygoim/pkg/strings"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -12,11 +16,12 @@ import (
 )
 
 // *连接logic层
-func (s *Server) Connect(c context.Context, p *protocol.Proto, cookie string) (mid int64, key, rid string, accepts []int32, heartbeat time.Duration, err error) {
+func (s *Server) Connect(c context.Context, p *protocol.Proto, cookie, ip string) (mid int64, key, rid string, accepts []int32, heartbeat time.Duration, err error) {
 	reply, err := s.rpcClient.Connect(c, &logic.ConnectReq{
 		Server: s.serverID,
 		Cookie: cookie,
 		Token:  p.Body,
+		Ip:     ip,
 	})
 	if err != nil {
 		return
@@ -60,20 +65,234 @@ func (s *Server) RenewOnline(ctx context.Context, serverID string, roomCount map
 }
 
 // *接受一个消息
-func (s *Server) Receive(ctx context.Context, mid int64, p *protocol.Proto) (err error) {
-	_, err = s.rpcClient.Receive(ctx, &logic.ReceiveReq{Mid: mid, Proto: p})
+func (s *Server) Receive(ctx context.Context, mid int64, key string, p *protocol.Proto) (err error) {
+	_, err = s.rpcClient.Receive(ctx, &logic.ReceiveReq{Mid: mid, Key: key, Server: s.serverID, Proto: p})
 	return
 }
 
+// *把未确认的消息交回logic存为离线消息
+func (s *Server) Unacked(ctx context.Context, mid int64, key string, protos []*protocol.Proto) (err error) {
+	if len(protos) == 0 {
+		return
+	}
+	if _, err = s.rpcClient.Unacked(ctx, &logic.UnackedReq{
+		Server: s.serverID,
+		Mid:    mid,
+		Key:    key,
+		Protos: protos,
+	}); err != nil {
+		logger.Error("hand back unacked failed",
+			zap.String("key", key),
+			zap.Int64("mid", mid),
+			zap.Int("count", len(protos)),
+			zap.Error(err),
+		)
+	}
+	return
+}
+
+// *从指定序列号开始同步错过的消息
+func (s *Server) Sync(ctx context.Context, mid int64, key string, seq int32) (protos []*protocol.Proto, err error) {
+	reply, err := s.rpcClient.Sync(ctx, &logic.SyncReq{
+		Server: s.serverID,
+		Mid:    mid,
+		Key:    key,
+		Seq:    seq,
+	})
+	if err != nil {
+		return
+	}
+	return reply.Protos, nil
+}
+
+// *获取房间最近的历史消息
+func (s *Server) RoomHistory(ctx context.Context, room string, limit int) (protos []*protocol.Proto, err error) {
+	reply, err := s.rpcClient.RoomHistory(ctx, &logic.RoomHistoryReq{
+		Room:  room,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return
+	}
+	return reply.Protos, nil
+}
+
+// *加入房间成功后把房间的历史消息放入信号通道,历史消息用单独的操作码,客户端可以和实时消息区分开
+func (s *Server) pushRoomHistory(ctx context.Context, ch *Channel, room string) {
+	if room == "" || !s.c.History.Open {
+		return
+	}
+	protos, err := s.RoomHistory(ctx, room, s.c.History.Limit)
+	if err != nil {
+		logger.Error("room history failed",
+			zap.String("room", room),
+			zap.String("key", ch.Key),
+			zap.Error(err),
+		)
+		return
+	}
+	if len(protos) == 0 {
+		return
+	}
+	_ = ch.Push(&protocol.Proto{Ver: 1, Op: protocol.OpRoomHistoryReply, Body: packProtos(protos)})
+}
+
+// *把多条消息依次打包成完整的协议包
+func packProtos(protos []*protocol.Proto) []byte {
+	buf := bytes.NewWriterSize(64)
+	for _, p := range protos {
+		p.WriteTo(buf)
+	}
+	return buf.Buffer()
+}
+
+// *发送点对点消息,返回服务端分配的消息ID和发送状态
+func (s *Server) SendMsg(ctx context.Context, mid int64, key string, p *protocol.Proto) (id string, status int32, err error) {
+	reply, err := s.rpcClient.SendMsg(ctx, &logic.SendMsgReq{
+		Server: s.serverID,
+		Mid:    mid,
+		Key:    key,
+		Proto:  p,
+	})
+	if err != nil {
+		return
+	}
+	return reply.Id, reply.Status, nil
+}
+
+// *加入房间前由logic鉴权,返回是否允许以及鉴权结果
+func (s *Server) AuthorizeRoom(ctx context.Context, mid int64, key, room, ticket string) (allow bool, code int32, err error) {
+	reply, err := s.rpcClient.AuthorizeRoom(ctx, &logic.AuthorizeRoomReq{
+		Server: s.serverID,
+		Mid:    mid,
+		Key:    key,
+		Room:   room,
+		Ticket: ticket,
+	})
+	if err != nil {
+		return false, protocol.RoomAuthFailed, err
+	}
+	return reply.Allow, reply.Code, nil
+}
+
+// *解析切换房间的消息体,可以是房间键,也可以是带密码或票据的JSON: {"room":"live://1000","ticket":"xxx"}
+func parseChangeRoom(body []byte) (room, ticket string) {
+	if len(body) > 0 && body[0] == '{' {
+		var arg struct {
+			Room   string `json:"room"`
+			Ticket string `json:"ticket"`
+		}
+		if err := json.Unmarshal(body, &arg); err == nil {
+			return arg.Room, arg.Ticket
+		}
+	}
+	return string(body), ""
+}
+
+// *解析获取房间历史消息的消息体,可以是条数,也可以是带房间的JSON: {"room":"live://1000","limit":20}
+func parseRoomHistory(body []byte) (room string, limit int, ok bool) {
+	if len(body) > 0 && body[0] == '{' {
+		var arg struct {
+			Room  string `json:"room"`
+			Limit int    `json:"limit"`
+		}
+		if err := json.Unmarshal(body, &arg); err != nil {
+			return "", 0, false
+		}
+		return arg.Room, arg.Limit, true
+	}
+	limit, err := strconv.Atoi(string(body))
+	return "", limit, err == nil
+}
+
+// *开启鉴权时由logic判断能否加入房间,返回鉴权结果
+func (s *Server) authorizeRoom(ctx context.Context, ch *Channel, room, ticket string) int32 {
+	if !s.c.Room.Authorize {
+		return protocol.RoomAuthOK
+	}
+	allow, code, err := s.AuthorizeRoom(ctx, ch.Mid, ch.Key, room, ticket)
+	if err != nil {
+		logger.Error("authorize room failed",
+			zap.String("room", room),
+			zap.Int64("mid", ch.Mid),
+			zap.Error(err),
+		)
+	}
+	if !allow && code == protocol.RoomAuthOK {
+		code = protocol.RoomAuthFailed
+	}
+	return code
+}
+
+// *加入房间的回复,只带房间和鉴权结果
+func roomReplyBody(room string, code int32) []byte {
+	b, _ := json.Marshal(map[string]interface{}{"room": room, "code": code})
+	return b
+}
+
 // *根据协议的操作码执行不同的操作
 func (s *Server) Operate(ctx context.Context, p *protocol.Proto, ch *Channel, b *Bucket) error {
 	switch p.Op {
 	case protocol.OpChangeRoom:
-		if err := b.ChangeRoom(string(p.Body), ch); err != nil {
+		room, ticket := parseChangeRoom(p.Body)
+		p.Op = protocol.OpChangeRoomReply
+		if room != "" {
+			if code := s.authorizeRoom(ctx, ch, room, ticket); code != protocol.RoomAuthOK {
+				//*鉴权不通过时不切换房间,回复中带上错误码
+				p.Body = roomReplyBody(room, code)
+				break
+			}
+		}
+		joined := ch.InRoom(room) != nil
+		if err := b.ChangeRoom(room, ch); err != nil {
 			logger.Error("change room failed",
-				zap.ByteString("b.ChangeRoom", p.Body), zap.Error(err))
+				zap.String("b.ChangeRoom", room), zap.Error(err))
+			p.Body = roomReplyBody(room, protocol.RoomAuthFailed)
+			break
 		}
-		p.Op = protocol.OpChangeRoomReply
+		//*切换成功后才推送历史消息,失败时不会收到不属于自己的房间的历史
+		if !joined {
+			s.pushRoomHistory(ctx, ch, room)
+		}
+		p.Body = roomReplyBody(room, protocol.RoomAuthOK)
+	case protocol.OpJoinRoom:
+		room, ticket := parseChangeRoom(p.Body)
+		p.Op = protocol.OpJoinRoomReply
+		if room == "" {
+			p.Body = roomReplyBody(room, protocol.RoomAuthFailed)
+			break
+		}
+		if ch.InRoom(room) != nil {
+			p.Body = roomReplyBody(room, protocol.RoomAuthOK)
+			break
+		}
+		if s.c.Room.Max > 0 && ch.RoomNum() >= s.c.Room.Max {
+			p.Body = roomReplyBody(room, protocol.RoomAuthFull)
+			break
+		}
+		if code := s.authorizeRoom(ctx, ch, room, ticket); code != protocol.RoomAuthOK {
+			p.Body = roomReplyBody(room, code)
+			break
+		}
+		if err := b.JoinRoom(room, ch, s.c.Room.Max); err != nil {
+			logger.Error("join room failed",
+				zap.String("room", room),
+				zap.String("key", ch.Key),
+				zap.Error(err),
+			)
+			code := protocol.RoomAuthFailed
+			if err == errors.ErrRoomFull {
+				code = protocol.RoomAuthFull
+			}
+			p.Body = roomReplyBody(room, code)
+			break
+		}
+		s.pushRoomHistory(ctx, ch, room)
+		//*请求中可能带有票据,回复中只带房间和结果
+		p.Body = roomReplyBody(room, protocol.RoomAuthOK)
+	case protocol.OpLeaveRoom:
+		b.LeaveRoom(string(p.Body), ch)
+		p.Op = protocol.OpLeaveRoomReply
 	case protocol.OpSub:
 		if ops, err := strings.SplitInt32s(string(p.Body), ","); err == nil {
 			ch.Watch(ops...)
@@ -84,8 +303,60 @@ func (s *Server) Operate(ctx context.Context, p *protocol.Proto, ch *Channel, b
 			ch.UnWatch(ops...)
 		}
 		p.Op = protocol.OpUnsubReply
+	case protocol.OpSync:
+		var protos []*protocol.Proto
+		if seq, err := strconv.ParseInt(string(p.Body), 10, 32); err == nil {
+			if protos, err = s.Sync(ctx, ch.Mid, ch.Key, int32(seq)); err != nil {
+				logger.Error("sync failed",
+					zap.Int64("mid", ch.Mid),
+					zap.Int64("seq", seq),
+					zap.Error(err),
+				)
+			}
+		}
+		//*回复中依次放入错过的消息的完整协议包
+		p.Op = protocol.OpSyncReply
+		p.Body = packProtos(protos)
+	case protocol.OpSendMsg:
+		var reply struct {
+			ID     string `json:"id"`
+			Status int32  `json:"status"`
+		}
+		var err error
+		if reply.ID, reply.Status, err = s.SendMsg(ctx, ch.Mid, ch.Key, p); err != nil {
+			logger.Error("send msg failed",
+				zap.Int64("mid", ch.Mid),
+				zap.Error(err),
+			)
+			reply.Status = protocol.SendMsgFailed
+		}
+		p.Op = protocol.OpSendMsgReply
+		p.Body, _ = json.Marshal(&reply)
+	case protocol.OpRoomHistory:
+		var protos []*protocol.Proto
+		if rid, limit, ok := parseRoomHistory(p.Body); ok {
+			//*没有指定房间时取主房间,指定的房间必须已经加入
+			var room *Room
+			if rid == "" {
+				room = ch.Room()
+			} else {
+				room = ch.InRoom(rid)
+			}
+			if room != nil {
+				var err error
+				if protos, err = s.RoomHistory(ctx, room.ID, limit); err != nil {
+					logger.Error("room history failed",
+						zap.String("room", room.ID),
+						zap.Int("limit", limit),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+		p.Op = protocol.OpRoomHistoryReply
+		p.Body = packProtos(protos)
 	default:
-		if err := s.Receive(ctx, ch.Mid, p); err != nil {
+		if err := s.Receive(ctx, ch.Mid, ch.Key, p); err != nil {
 			logger.Error("report operation failed",
 				zap.Int64("mid", ch.Mid),
 				zap.Int32("op", p.Op),
@@ -0,0 +1,211 @@
+package comet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+)
+
+// *慢消费者策略
+const (
+	policyDropNewest = "drop_newest" //*丢弃新消息
+	policyDropOldest = "drop_oldest" //*丢弃同类最旧的消息
+	policyCoalesce   = "coalesce"    //*用新消息替换同操作码的旧消息
+	policyDisconnect = "disconnect"  //*断开连接
+
+	// *不属于任何分类的操作码的分类名称
+	defaultPushClass = "default"
+	// *断开和转移通知的分类名称,这类消息不受字节数上限限制
+	controlPushClass = "control"
+	// *协议头的大小,计入推送缓冲的字节数
+	protoHeaderSize = 16
+	// *OpRaw消息体中第一条消息的操作码的偏移量: 包长4、头长2、版本2
+	rawOpOffset = 8
+)
+
+// *一类操作码的策略
+type pushClass struct {
+	name   string
+	policy string
+}
+
+// *推送缓冲的策略
+type pushPolicy struct {
+	maxBytes int
+	def      *pushClass
+	classes  map[int32]*pushClass
+}
+
+// *控制消息的分类,不能通过配置修改
+var controlClass = &pushClass{name: controlPushClass}
+
+// *断开和转移通知是否为控制消息,慢消费者也必须收到,否则客户端不知道为什么被断开或该连到哪里
+func isControlOp(op int32) bool {
+	return op == protocol.OpDisconnectReply || op == protocol.OpRedirect
+}
+
+// *未设置策略的连接使用的默认策略
+var defaultPushPolicy, _ = newPushPolicy(conf.Default().Push)
+
+func checkPolicy(policy string) error {
+	switch policy {
+	case policyDropNewest, policyDropOldest, policyCoalesce, policyDisconnect:
+		return nil
+	}
+	return fmt.Errorf("unknown push policy: %s", policy)
+}
+
+// *根据配置生成推送缓冲的策略
+func newPushPolicy(c *conf.Push) (pp *pushPolicy, err error) {
+	if err = checkPolicy(c.Policy); err != nil {
+		return
+	}
+	pp = &pushPolicy{
+		maxBytes: c.MaxBytes,
+		def:      &pushClass{name: defaultPushClass, policy: c.Policy},
+		classes:  make(map[int32]*pushClass),
+	}
+	for _, cc := range c.Classes {
+		if err = checkPolicy(cc.Policy); err != nil {
+			return nil, err
+		}
+		cls := &pushClass{name: cc.Name, policy: cc.Policy}
+		for _, op := range cc.Ops {
+			if isControlOp(op) {
+				return nil, fmt.Errorf("op %d is a control op and can not be in a push class", op)
+			}
+			if _, ok := pp.classes[op]; ok {
+				return nil, fmt.Errorf("op %d in more than one push class", op)
+			}
+			pp.classes[op] = cls
+		}
+	}
+	return
+}
+
+// *操作码所属的分类
+func (pp *pushPolicy) class(op int32) *pushClass {
+	if isControlOp(op) {
+		return controlClass
+	}
+	if cls, ok := pp.classes[op]; ok {
+		return cls
+	}
+	return pp.def
+}
+
+// *用于分类的操作码,job打包的OpRaw取第一条消息的操作码
+func pushOp(p *protocol.Proto) int32 {
+	if p.Op == protocol.OpRaw && len(p.Body) >= protoHeaderSize {
+		return int32(binary.BigEndian.Uint32(p.Body[rawOpOffset:]))
+	}
+	return p.Op
+}
+
+// *推送队列中的消息
+type queuedMsg struct {
+	p     *protocol.Proto
+	op    int32
+	class *pushClass
+	size  int
+}
+
+// *连接的推送队列,按字节数限制待发送的消息
+type pushQueue struct {
+	mutex   sync.Mutex
+	msgs    []*queuedMsg
+	bytes   int  //*待发送消息的字节数
+	evicted bool //*已按disconnect策略断开,之后的消息直接丢弃
+}
+
+// *放入队列,超过上限时按消息所属分类的策略处理,新消息被丢弃时返回错误。
+// *控制消息计入字节数但不受上限限制,也不会被其他分类的drop_oldest挤掉
+func (q *pushQueue) push(pp *pushPolicy, p *protocol.Proto) error {
+	var (
+		op   = pushOp(p)
+		cls  = pp.class(op)
+		size = len(p.Body) + protoHeaderSize
+	)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.evicted {
+		atomic.AddUint64(&signalDropped, 1)
+		return errors.ErrSlowConsumer
+	}
+	//*单条消息超过上限时只要队列为空也放入,否则永远发不出去;控制消息总是放入
+	if cls == controlClass || len(q.msgs) == 0 || q.bytes+size <= pp.maxBytes {
+		q.append(p, op, cls, size)
+		return nil
+	}
+	switch cls.policy {
+	case policyDropOldest:
+		for i := 0; i < len(q.msgs) && q.bytes+size > pp.maxBytes; {
+			if q.msgs[i].class != cls {
+				i++
+				continue
+			}
+			q.bytes -= q.msgs[i].size
+			q.msgs = append(q.msgs[:i], q.msgs[i+1:]...)
+			atomic.AddUint64(&signalDropped, 1)
+			metricSlowConsumer.WithLabelValues(cls.name, policyDropOldest).Inc()
+		}
+		if len(q.msgs) == 0 || q.bytes+size <= pp.maxBytes {
+			q.append(p, op, cls, size)
+			return nil
+		}
+	case policyCoalesce:
+		for i := len(q.msgs) - 1; i >= 0; i-- {
+			if m := q.msgs[i]; m.op == op {
+				q.bytes += size - m.size
+				m.p, m.size = p, size
+				atomic.AddUint64(&signalDropped, 1)
+				metricSlowConsumer.WithLabelValues(cls.name, policyCoalesce).Inc()
+				return nil
+			}
+		}
+	case policyDisconnect:
+		//*清空队列,只发送断开通知,发送协程发完后关闭连接
+		atomic.AddUint64(&signalDropped, uint64(len(q.msgs)+1))
+		q.msgs, q.bytes = q.msgs[:0], 0
+		body, _ := json.Marshal(map[string]int32{"reason": protocol.KickReasonSlow})
+		q.append(&protocol.Proto{Ver: 1, Op: protocol.OpDisconnectReply, Body: body}, protocol.OpDisconnectReply, cls, len(body)+protoHeaderSize)
+		q.evicted = true
+		metricSlowConsumer.WithLabelValues(cls.name, policyDisconnect).Inc()
+		return errors.ErrSlowConsumer
+	}
+	atomic.AddUint64(&signalDropped, 1)
+	metricSlowConsumer.WithLabelValues(cls.name, policyDropNewest).Inc()
+	return errors.ErrSignalFullMsgDropped
+}
+
+func (q *pushQueue) append(p *protocol.Proto, op int32, cls *pushClass, size int) {
+	q.msgs = append(q.msgs, &queuedMsg{p: p, op: op, class: cls, size: size})
+	q.bytes += size
+}
+
+// *取出最早的消息,返回是否还有待发送的消息或需要关闭连接
+func (q *pushQueue) pop() (p *protocol.Proto, more bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if len(q.msgs) > 0 {
+		m := q.msgs[0]
+		q.msgs[0] = nil
+		q.msgs = q.msgs[1:]
+		q.bytes -= m.size
+		p = m.p
+	}
+	return p, len(q.msgs) > 0 || q.evicted
+}
+
+// *是否已按disconnect策略断开
+func (q *pushQueue) isEvicted() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.evicted
+}
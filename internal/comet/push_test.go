@@ -0,0 +1,168 @@
+package comet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+	"github.com/gyy0727/mygoim/pkg/bytes"
+)
+
+// 测试各个慢消费者策略,每条消息按协议头计16字节,上限放得下两条空消息
+func TestPushPolicy(t *testing.T) {
+	pp, err := newPushPolicy(&conf.Push{
+		MaxBytes: 2 * protoHeaderSize,
+		Policy:   policyDropNewest,
+		Classes: []*conf.PushClass{
+			{Name: "old", Ops: []int32{1001}, Policy: policyDropOldest},
+			{Name: "merge", Ops: []int32{1002}, Policy: policyCoalesce},
+			{Name: "slow", Ops: []int32{1003}, Policy: policyDisconnect},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	push := func(ch *Channel, op, seq int32) error {
+		return ch.Push(&protocol.Proto{Op: op, Seq: seq})
+	}
+	ready := func(ch *Channel) (seqs []int32) {
+		for ch.queue.bytes > 0 {
+			seqs = append(seqs, ch.Ready().Seq)
+		}
+		return
+	}
+
+	ch := NewChannel(5, 10)
+	ch.policy = pp
+	push(ch, 1000, 1)
+	push(ch, 1000, 2)
+	if err = push(ch, 1000, 3); err != errors.ErrSignalFullMsgDropped {
+		t.Fatalf("drop newest error(%v)", err)
+	}
+	if seqs := ready(ch); len(seqs) != 2 || seqs[1] != 2 {
+		t.Fatalf("drop newest got %v", seqs)
+	}
+
+	ch = NewChannel(5, 10)
+	ch.policy = pp
+	push(ch, 1001, 1)
+	push(ch, 1001, 2)
+	if err = push(ch, 1001, 3); err != nil {
+		t.Fatalf("drop oldest error(%v)", err)
+	}
+	if seqs := ready(ch); len(seqs) != 2 || seqs[0] != 2 || seqs[1] != 3 {
+		t.Fatalf("drop oldest got %v", seqs)
+	}
+
+	ch = NewChannel(5, 10)
+	ch.policy = pp
+	push(ch, 1002, 1)
+	push(ch, 1000, 2)
+	if err = push(ch, 1002, 3); err != nil {
+		t.Fatalf("coalesce error(%v)", err)
+	}
+	if seqs := ready(ch); len(seqs) != 2 || seqs[0] != 3 || seqs[1] != 2 {
+		t.Fatalf("coalesce got %v", seqs)
+	}
+
+	ch = NewChannel(5, 10)
+	ch.policy = pp
+	push(ch, 1000, 1)
+	push(ch, 1000, 2)
+	if err = push(ch, 1003, 3); err != errors.ErrSlowConsumer {
+		t.Fatalf("disconnect error(%v)", err)
+	}
+	if p := ch.Ready(); p.Op != protocol.OpDisconnectReply {
+		t.Fatalf("disconnect got op %d", p.Op)
+	}
+	if p := ch.Ready(); p != protocol.ProtoFinish {
+		t.Fatalf("disconnect got %v; want finish", p)
+	}
+}
+
+// 测试断开和转移通知不受字节数上限限制,也不会被drop_oldest挤掉
+func TestPushControl(t *testing.T) {
+	if _, err := newPushPolicy(&conf.Push{
+		Policy:  policyDropNewest,
+		Classes: []*conf.PushClass{{Name: "control", Ops: []int32{protocol.OpRedirect}, Policy: policyDropOldest}},
+	}); err == nil {
+		t.Fatal("control op in push class should be rejected")
+	}
+	pp, err := newPushPolicy(&conf.Push{
+		MaxBytes: 2 * protoHeaderSize,
+		Policy:   policyDropOldest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := NewChannel(5, 10)
+	ch.policy = pp
+	ch.Push(&protocol.Proto{Op: 1000, Seq: 1})
+	ch.Push(&protocol.Proto{Op: 1000, Seq: 2})
+	if err = ch.Push(&protocol.Proto{Op: protocol.OpRedirect, Seq: 3}); err != nil {
+		t.Fatalf("redirect error(%v)", err)
+	}
+	if err = ch.Push(&protocol.Proto{Op: 1000, Seq: 4}); err != nil {
+		t.Fatalf("drop oldest error(%v)", err)
+	}
+	var seqs []int32
+	for ch.queue.bytes > 0 {
+		seqs = append(seqs, ch.Ready().Seq)
+	}
+	if len(seqs) != 2 || seqs[0] != 3 || seqs[1] != 4 {
+		t.Fatalf("control got %v; want [3 4]", seqs)
+	}
+}
+
+// 测试关闭前先发完推送队列中的消息
+func TestReadyBeforeFinish(t *testing.T) {
+	ch := NewChannel(5, 10)
+	ch.Push(&protocol.Proto{Op: 1000, Seq: 1})
+	ch.Push(&protocol.Proto{Op: protocol.OpDisconnectReply, Seq: 2})
+	ch.Close()
+	for _, seq := range []int32{1, 2} {
+		if p := ch.Ready(); p.Seq != seq {
+			t.Fatalf("ready seq %d; want %d", p.Seq, seq)
+		}
+	}
+	if p := ch.Ready(); p != protocol.ProtoFinish {
+		t.Fatalf("ready %v; want finish", p)
+	}
+}
+
+// 测试信号通道已满时推送的消息不会滞留在队列中
+func TestReadyWakeup(t *testing.T) {
+	ch := NewChannel(5, 1)
+	ch.Signal()
+	ch.Push(&protocol.Proto{Op: 1000, Seq: 1})
+	if p := ch.Ready(); p != protocol.ProtoReady {
+		t.Fatalf("ready %v; want ProtoReady", p)
+	}
+	done := make(chan *protocol.Proto, 1)
+	go func() { done <- ch.Ready() }()
+	select {
+	case p := <-done:
+		if p.Seq != 1 {
+			t.Fatalf("ready seq %d; want 1", p.Seq)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued message stranded")
+	}
+	// 队列为空时等待下一次推送
+	go func() { done <- ch.Ready() }()
+	ch.Push(&protocol.Proto{Op: 1000, Seq: 2})
+	if p := <-done; p.Seq != 2 {
+		t.Fatalf("ready seq %d; want 2", p.Seq)
+	}
+}
+
+// 测试OpRaw按第一条消息的操作码分类
+func TestPushOp(t *testing.T) {
+	w := bytes.NewWriterSize(64)
+	(&protocol.Proto{Ver: 1, Op: 1002, Body: []byte("msg")}).WriteTo(w)
+	if op := pushOp(&protocol.Proto{Op: protocol.OpRaw, Body: w.Buffer()}); op != 1002 {
+		t.Fatalf("raw op %d; want 1002", op)
+	}
+}
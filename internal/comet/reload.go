@@ -0,0 +1,39 @@
+package comet
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"go.uber.org/zap"
+)
+
+// *握手超时
+func (s *Server) HandshakeTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.handshake))
+}
+
+// *热加载可以在运行时修改的配置: 握手超时、白名单、日志级别、Env.Offline和Env.Weight,
+// *nc需要先通过Validate校验,其他配置需要重启才能生效。comet没有限流配置,没有可以热加载的限流项
+func (s *Server) Reload(nc *conf.Config) {
+	s.confMutex.Lock()
+	defer s.confMutex.Unlock()
+	atomic.StoreInt64(&s.handshake, int64(nc.Protocol.HandshakeTimeout))
+	s.c.Protocol.HandshakeTimeout = nc.Protocol.HandshakeTimeout
+	if whitelist != nil {
+		whitelist.Reload(s.c.Whitelist.Whitelist, nc.Whitelist.Whitelist)
+	}
+	s.c.Whitelist.Whitelist = nc.Whitelist.Whitelist
+	if err := SetLogLevel(nc.Log.Level); err == nil {
+		s.c.Log.Level = nc.Log.Level
+	}
+	s.c.Env.Offline = nc.Env.Offline
+	s.c.Env.Weight = nc.Env.Weight
+	logger.Info("config reloaded",
+		zap.Duration("handshake_timeout", s.HandshakeTimeout()),
+		zap.Int64s("whitelist", nc.Whitelist.Whitelist),
+		zap.String("log_level", nc.Log.Level),
+		zap.Bool("offline", nc.Env.Offline),
+		zap.Int64("weight", nc.Env.Weight),
+	)
+}
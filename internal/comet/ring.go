@@ -67,6 +67,7 @@ func (r *Ring) GetAdv() {
 func (r *Ring) Set() (proto *protocol.Proto, err error) {
 	//*已满
 	if r.wp-r.rp >= r.num {
+		metricRingFull.Inc()
 		return nil, errors.ErrRingFull
 	}
 	proto = &r.data[r.wp&r.mask]
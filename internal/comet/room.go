@@ -9,12 +9,12 @@ import (
 )
 
 type Room struct {
-	ID        string       //*房间名称
-	rLock     sync.RWMutex //*读锁
-	next      *Channel     //*指向房间中双向链表的头指针
-	drop      bool         //*是否已被丢弃
-	Online    int32        //*房间在线用户的数量
-	AllOnline int32        //*历史在线用户数量
+	ID        string                //*房间名称
+	rLock     sync.RWMutex          //*读锁
+	chs       map[*Channel]struct{} //*房间中的所有连接,一个连接可以同时在多个房间中
+	drop      bool                  //*是否已被丢弃
+	Online    int32                 //*房间在线用户的数量
+	AllOnline int32                 //*历史在线用户数量
 }
 
 func NewRoom(id string) (r *Room) {
@@ -22,7 +22,7 @@ func NewRoom(id string) (r *Room) {
 	r = new(Room)
 	r.ID = id
 	r.drop = false
-	r.next = nil
+	r.chs = make(map[*Channel]struct{})
 	r.Online = 0
 	return
 }
@@ -30,16 +30,11 @@ func NewRoom(id string) (r *Room) {
 // *用于将 Channel 添加到 Room 中
 func (r *Room) Put(ch *Channel) (err error) {
 	r.rLock.Lock()
-	//*如果房间没有被丢弃,则将 Channel 插入到双向链表的头部
+	//*如果房间没有被丢弃,则将 Channel 加入房间
 	if !r.drop {
-		if r.next != nil {
-			r.next.Prev = ch
-		}
-		ch.Next = r.next
-		ch.Prev = nil
-		r.next = ch
-		//*增加在线用户的数量
-		r.Online++
+		r.chs[ch] = struct{}{}
+		//*更新在线用户的数量
+		r.Online = int32(len(r.chs))
 	} else {
 		err = errors.ErrRoomDroped
 	}
@@ -50,20 +45,10 @@ func (r *Room) Put(ch *Channel) (err error) {
 
 // *删除通道,返回房间是否还存在活跃用户
 func (r *Room) Del(ch *Channel) bool {
-	//*将传入的通道的前后项通过双向链表相连
 	r.rLock.Lock()
-	if ch.Next != nil {
-		ch.Next.Prev = ch.Prev
-	}
-	if ch.Prev != nil {
-		ch.Prev.Next = ch.Next
-	} else {
-		r.next = ch.Next
-	}
-	ch.Next = nil
-	ch.Prev = nil
+	delete(r.chs, ch)
 	//*更新在线人数和drop
-	r.Online--
+	r.Online = int32(len(r.chs))
 	r.drop = r.Online == 0
 	r.rLock.Unlock()
 	logger.Info("房间删除通道", zap.String("房间id", r.ID), zap.Int32("房间在线人数", r.Online))
@@ -73,7 +58,7 @@ func (r *Room) Del(ch *Channel) bool {
 // *广播消息
 func (r *Room) Push(p *protocol.Proto) {
 	r.rLock.RLock()
-	for ch := r.next; ch != nil; ch = ch.Next {
+	for ch := range r.chs {
 		_ = ch.Push(p)
 	}
 	logger.Info("房间广播消息", zap.String("房间id", r.ID), zap.Int32("房间在线人数", r.Online))
@@ -83,13 +68,31 @@ func (r *Room) Push(p *protocol.Proto) {
 // *用于关闭房间中的所有 Channel
 func (r *Room) Close() {
 	r.rLock.RLock()
-	for ch := r.next; ch != nil; ch = ch.Next {
+	for ch := range r.chs {
 		ch.Close()
 	}
 	logger.Info("房间关闭所有channel", zap.String("房间id", r.ID), zap.Int32("房间在线人数", r.Online))
 	r.rLock.RUnlock()
 }
 
+// *返回房间中所有连接的快照
+func (r *Room) Channels() (chs []*Channel) {
+	r.rLock.RLock()
+	chs = make([]*Channel, 0, len(r.chs))
+	for ch := range r.chs {
+		chs = append(chs, ch)
+	}
+	r.rLock.RUnlock()
+	return
+}
+
+// *用于获取本机房间的在线连接数
+func (r *Room) OnlineCount() int32 {
+	r.rLock.RLock()
+	defer r.rLock.RUnlock()
+	return r.Online
+}
+
 // *用于获取房间的在线用户数
 func (r *Room) OnlineNum() int32 {
 	if r.AllOnline > 0 {
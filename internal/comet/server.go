@@ -3,6 +3,8 @@ package comet
 import (
 	"context"
 	"math/rand"
+	"net"
+	"sync"
 	"time"
 	"github.com/gyy0727/mygoim/api/logic"
 	"github.com/gyy0727/mygoim/internal/comet/conf"
@@ -43,6 +45,7 @@ func newLogicClient(c *conf.RPCClient) logic.LogicClient {
 				Timeout:             grpcKeepAliveTimeout,
 				PermitWithoutStream: true,
 			}),
+			grpc.WithUnaryInterceptor(metricLogicInterceptor),
 			grpc.WithDefaultServiceConfig(`{
                 "loadBalancingConfig": [{"round_robin": {}}]
             }`),
@@ -61,6 +64,15 @@ type Server struct {
 	bucketIdx uint32            //*当前服务器包含的bucket的数量
 	serverID  string            //*服务实例唯一标识
 	rpcClient logic.LogicClient //*gRPC客户端接口
+	ackOps    map[int32]struct{} //*需要客户端确认的操作码
+	started   time.Time          //*启动时间
+	confMutex sync.RWMutex       //*热加载时修改配置的锁
+	handshake int64              //*握手超时,可以热加载
+	listeners []net.Listener     //*TCP和WebSocket的监听器,关闭时停止接受新连接
+	lisMutex  sync.Mutex         //*listeners的锁
+	draining  int32              //*是否正在优雅关闭
+	serving   int64              //*正在处理的连接数,包括握手中的连接
+	push      *pushPolicy        //*推送缓冲的策略
 }
 
 // *新建一个server
@@ -69,6 +81,8 @@ func NewServer(c *conf.Config) *Server {
 		c:         c,
 		round:     NewRound(c),
 		rpcClient: newLogicClient(c.RPCClient),
+		started:   time.Now(),
+		handshake: int64(c.Protocol.HandshakeTimeout),
 	}
 	s.buckets = make([]*Bucket, c.Bucket.Size)
 	s.bucketIdx = uint32(c.Bucket.Size)
@@ -77,6 +91,16 @@ func NewServer(c *conf.Config) *Server {
 		s.buckets[i] = NewBucket(c.Bucket)
 	}
 	s.serverID = c.Env.Host //*当前主机的主机名
+	var err error
+	if s.push, err = newPushPolicy(c.Push); err != nil {
+		panic(err)
+	}
+	s.ackOps = make(map[int32]struct{})
+	if c.Ack != nil && c.Ack.Open {
+		for _, op := range c.Ack.Ops {
+			s.ackOps[op] = struct{}{}
+		}
+	}
 	logger.Info("初始化server",zap.Int("bucket size",c.Bucket.Size))
 	go s.onlineproc()
 	return s
@@ -106,11 +130,6 @@ func (s *Server) RandServerHearbeat() time.Duration {
 	return (minServerHeartbeat + time.Duration(rand.Int63n(int64(maxServerHeartbeat-minServerHeartbeat))))
 }
 
-//*关闭server 
-func (s *Server) Close() (err error) {
-	return
-}
-
 //*server的主函数, 用于处理在线人数的更新
 func (s *Server) onlineproc() {
 	logger.Info("onlineproc执行中")
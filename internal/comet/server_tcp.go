@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	log "github.com/golang/glog"
@@ -47,6 +48,7 @@ func InitTCP(server *Server, addrs []string, accept int) (err error) {
 		logger.Info("Start TCP listen",
 			zap.String("bind", bind),
 		)
+		server.addListener(listener)
 		//*accept每个地址启动的 goroutine 数量，用于并发处理客户端连接
 		for i := 0; i < accept; i++ {
 			go acceptTCP(server, listener)
@@ -64,6 +66,9 @@ func acceptTCP(server *Server, lis *net.TCPListener) {
 	)
 	for {
 		if conn, err = lis.AcceptTCP(); err != nil {
+			if server.Draining() {
+				return
+			}
 			logger.Error("Failed to accept connection",
 				zap.String("address", lis.Addr().String()),
 				zap.Error(err),
@@ -132,6 +137,11 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 		rr      = &ch.Reader                                               //*读缓冲区的 Reader
 		wr      = &ch.Writer                                               //*写缓冲区的 Writer
 	)
+	ch.policy = s.push
+	ch.conn = conn
+	metricConnections.WithLabelValues(protoTCP).Inc()
+	atomic.AddInt64(&s.serving, 1)
+	defer atomic.AddInt64(&s.serving, -1)
 	ch.Reader.ResetBuffer(conn, rb.Bytes())
 	ch.Writer.ResetBuffer(conn, wb.Bytes())
 	//*创建上下文，用于控制 goroutine 的生命周期。
@@ -139,7 +149,7 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 	defer cancel()
 	//*作用：设置握手超时定时器，并记录客户端 IP
 	step := 0
-	trd = tr.Add(time.Duration(s.c.Protocol.HandshakeTimeout), func() {
+	trd = tr.Add(s.HandshakeTimeout(), func() {
 		conn.Close()
 		logger.Error("tcp handshake timeout",
 			zap.String("key", ch.Key),
@@ -151,11 +161,13 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 	ch.IP, _, _ = net.SplitHostPort(conn.RemoteAddr().String())
 	//*进行客户端认证，并初始化客户端连接
 	step = 1
+	hsStart := time.Now()
 	//*p用于写一个消息到协议缓冲区
 	if p, err = ch.CliProto.Set(); err == nil {
 		//*其实就是将
-		if ch.Mid, ch.Key, rid, accepts, hb, err = s.authTCP(ctx, rr, wr, p); err == nil {
+		if ch.Mid, ch.Key, rid, accepts, hb, err = s.authTCP(ctx, rr, wr, p, ch.IP); err == nil {
 			ch.Watch(accepts...)
+			s.pushRoomHistory(ctx, ch, rid)
 			b = s.Bucket(ch.Key)
 			err = b.Put(rid, ch)
 			if conf.Conf.Debug {
@@ -168,6 +180,8 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 		}
 	}
 	step = 2
+	metricHandshakeDuration.WithLabelValues(protoTCP).Observe(time.Since(hsStart).Seconds())
+	metricHandshakes.WithLabelValues(protoTCP, metricResult(err)).Inc()
 	if err != nil {
 		conn.Close()
 		rp.Put(rb)
@@ -181,7 +195,9 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 	}
 	trd.Key = ch.Key
 	tr.Set(trd, hb)
-	white = whitelist.Contains(ch.Mid)
+	atrd := s.startAck(ch, tr, conn)
+	wver := whitelist.Version()
+	white = whitelist.Match(ch.Mid, ch.Key)
 	if white {
 		whitelist.Printf("key: %s[%s] auth\n", ch.Key, rid)
 	}
@@ -189,6 +205,7 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 	go s.dispatchTCP(conn, wr, wp, wb, ch)
 	serverHeartbeat := s.RandServerHearbeat()
 	for {
+		white = whitelist.Refresh(&wver, ch.Mid, ch.Key, white)
 		if p, err = ch.CliProto.Set(); err != nil {
 			break
 		}
@@ -198,10 +215,17 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 		if err = p.ReadTCP(rr); err != nil {
 			break
 		}
+		metricReads.WithLabelValues(protoTCP, metricOp(p.Op)).Inc()
 		if white {
 			whitelist.Printf("key: %s read proto:%v\n", ch.Key, p)
 		}
+		if p.Op == protocol.OpPushMsgAck {
+			//*确认消息不需要回复,槽位留给下一条
+			s.Ack(ch, p.Seq)
+			continue
+		}
 		if p.Op == protocol.OpHeartbeat {
+			metricHeartbeats.WithLabelValues(protoTCP).Inc()
 			tr.Set(trd, hb)
 			p.Op = protocol.OpHeartbeatReply
 			p.Body = nil
@@ -242,12 +266,14 @@ func (s *Server) ServeTCP(conn *net.TCPConn, rp, wp *bytes.Pool, tr *xtime.Timer
 	}
 	b.Del(ch)
 	tr.Del(trd)
+	unacked := s.stopAck(ch, tr, atrd)
 	rp.Put(rb)
 	conn.Close()
 	ch.Close()
 	if err = s.Disconnect(ctx, ch.Mid, ch.Key); err != nil {
 		log.Errorf("key: %s mid: %d operator do disconnect error(%v)", ch.Key, ch.Mid, err)
 	}
+	_ = s.Unacked(ctx, ch.Mid, ch.Key, unacked)
 	if white {
 		whitelist.Printf("key: %s mid: %d disconnect error(%v)\n", ch.Key, ch.Mid, err)
 	}
@@ -262,12 +288,14 @@ func (s *Server) dispatchTCP(conn *net.TCPConn, wr *bufio.Writer, wp *bytes.Pool
 		err    error
 		finish bool
 		online int32
-		white  = whitelist.Contains(ch.Mid)
+		wver   = whitelist.Version()
+		white  = whitelist.Match(ch.Mid, ch.Key)
 	)
 	if conf.Conf.Debug {
 		log.Infof("key: %s start dispatch tcp goroutine", ch.Key)
 	}
 	for {
+		white = whitelist.Refresh(&wver, ch.Mid, ch.Key, white)
 		if white {
 			whitelist.Printf("key: %s wait proto ready\n", ch.Key)
 		}
@@ -297,8 +325,8 @@ func (s *Server) dispatchTCP(conn *net.TCPConn, wr *bufio.Writer, wp *bytes.Pool
 					whitelist.Printf("key: %s start write client proto%v\n", ch.Key, p)
 				}
 				if p.Op == protocol.OpHeartbeatReply {
-					if ch.Room != nil {
-						online = ch.Room.OnlineNum()
+					if room := ch.Room(); room != nil {
+						online = room.OnlineNum()
 					}
 					if err = p.WriteTCPHeart(wr, online); err != nil {
 						goto failed
@@ -308,6 +336,7 @@ func (s *Server) dispatchTCP(conn *net.TCPConn, wr *bufio.Writer, wp *bytes.Pool
 						goto failed
 					}
 				}
+				metricWrites.WithLabelValues(protoTCP, metricOp(p.Op)).Inc()
 				if white {
 					whitelist.Printf("key: %s write client proto%v\n", ch.Key, p)
 				}
@@ -322,6 +351,7 @@ func (s *Server) dispatchTCP(conn *net.TCPConn, wr *bufio.Writer, wp *bytes.Pool
 			if err = p.WriteTCP(wr); err != nil {
 				goto failed
 			}
+			metricWrites.WithLabelValues(protoTCP, metricOp(p.Op)).Inc()
 			if white {
 				whitelist.Printf("key: %s write server proto%v\n", ch.Key, p)
 			}
@@ -333,7 +363,10 @@ func (s *Server) dispatchTCP(conn *net.TCPConn, wr *bufio.Writer, wp *bytes.Pool
 			whitelist.Printf("key: %s start flush \n", ch.Key)
 		}
 
-		if err = wr.Flush(); err != nil {
+		start := time.Now()
+		err = wr.Flush()
+		metricFlushDuration.WithLabelValues(protoTCP).Observe(time.Since(start).Seconds())
+		if err != nil {
 			break
 		}
 		if white {
@@ -357,7 +390,7 @@ failed:
 	}
 }
 
-func (s *Server) authTCP(ctx context.Context, rr *bufio.Reader, wr *bufio.Writer, p *protocol.Proto) (mid int64, key, rid string, accepts []int32, hb time.Duration, err error) {
+func (s *Server) authTCP(ctx context.Context, rr *bufio.Reader, wr *bufio.Writer, p *protocol.Proto, ip string) (mid int64, key, rid string, accepts []int32, hb time.Duration, err error) {
 	for {
 		if err = p.ReadTCP(rr); err != nil {
 			return
@@ -368,7 +401,7 @@ func (s *Server) authTCP(ctx context.Context, rr *bufio.Reader, wr *bufio.Writer
 			log.Errorf("tcp request operation(%d) not auth", p.Op)
 		}
 	}
-	if mid, key, rid, accepts, hb, err = s.Connect(ctx, p, ""); err != nil {
+	if mid, key, rid, accepts, hb, err = s.Connect(ctx, p, "", ip); err != nil {
 		log.Errorf("authTCP.Connect(key:%v).err(%v)", key, err)
 		return
 	}
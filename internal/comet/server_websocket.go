@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	log "github.com/golang/glog"
@@ -33,6 +34,7 @@ func InitWebsocket(server *Server, addrs []string, accept int) (err error) {
 			return
 		}
 		log.Infof("start ws listen: %s", bind)
+		server.addListener(listener)
 		for i := 0; i < accept; i++ {
 			//*给每个监听地址分配多个accept协程
 			go acceptWebsocket(server, listener)
@@ -67,6 +69,7 @@ func InitWebsocketWithTLS(server *Server, addrs []string, certFile, privateFile
 			return
 		}
 		log.Infof("start wss listen: %s", bind)
+		server.addListener(listener)
 		for i := 0; i < accept; i++ {
 			go acceptWebsocketWithTLS(server, listener)
 		}
@@ -83,7 +86,9 @@ func acceptWebsocket(server *Server, lis *net.TCPListener) {
 	)
 	for {
 		if conn, err = lis.AcceptTCP(); err != nil {
-	
+			if server.Draining() {
+				return
+			}
 			log.Errorf("listener.Accept(%s) error(%v)", lis.Addr().String(), err)
 			return
 		}
@@ -115,6 +120,9 @@ func acceptWebsocketWithTLS(server *Server, lis net.Listener) {
 	)
 	for {
 		if conn, err = lis.Accept(); err != nil {
+			if server.Draining() {
+				return
+			}
 			log.Errorf("listener.Accept(\"%s\") error(%v)", lis.Addr().String(), err)
 			return
 		}
@@ -160,12 +168,18 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 		req     *websocket.Request
 	)
 
+	ch.policy = s.push
+	ch.conn = conn
+	metricConnections.WithLabelValues(protoWebsocket).Inc()
+	atomic.AddInt64(&s.serving, 1)
+	defer atomic.AddInt64(&s.serving, -1)
+	hsStart := time.Now()
 	ch.Reader.ResetBuffer(conn, rb.Bytes())
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	
 	step := 0
-	trd = tr.Add(time.Duration(s.c.Protocol.HandshakeTimeout), func() {
+	trd = tr.Add(s.HandshakeTimeout(), func() {
 		_ = conn.SetDeadline(time.Now().Add(time.Millisecond * 100))
 		_ = conn.Close()
 		log.Errorf("key: %s remoteIP: %s step: %d ws handshake timeout", ch.Key, conn.RemoteAddr().String(), step)
@@ -174,6 +188,7 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 	ch.IP, _, _ = net.SplitHostPort(conn.RemoteAddr().String())
 	step = 1
 	if req, err = websocket.ReadRequest(rr); err != nil || req.RequestURI != "/sub" {
+		metricHandshakes.WithLabelValues(protoWebsocket, "failed").Inc()
 		conn.Close()
 		tr.Del(trd)
 		rp.Put(rb)
@@ -187,6 +202,7 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 	ch.Writer.ResetBuffer(conn, wb.Bytes())
 	step = 2
 	if ws, err = websocket.Upgrade(conn, rr, wr, req); err != nil {
+		metricHandshakes.WithLabelValues(protoWebsocket, "failed").Inc()
 		conn.Close()
 		tr.Del(trd)
 		rp.Put(rb)
@@ -199,8 +215,9 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 	
 	step = 3
 	if p, err = ch.CliProto.Set(); err == nil {
-		if ch.Mid, ch.Key, rid, accepts, hb, err = s.authWebsocket(ctx, ws, p, req.Header.Get("Cookie")); err == nil {
+		if ch.Mid, ch.Key, rid, accepts, hb, err = s.authWebsocket(ctx, ws, p, req.Header.Get("Cookie"), ch.IP); err == nil {
 			ch.Watch(accepts...)
+			s.pushRoomHistory(ctx, ch, rid)
 			b = s.Bucket(ch.Key)
 			err = b.Put(rid, ch)
 			if conf.Conf.Debug {
@@ -209,6 +226,8 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 		}
 	}
 	step = 4
+	metricHandshakeDuration.WithLabelValues(protoWebsocket).Observe(time.Since(hsStart).Seconds())
+	metricHandshakes.WithLabelValues(protoWebsocket, metricResult(err)).Inc()
 	if err != nil {
 		ws.Close()
 		rp.Put(rb)
@@ -221,7 +240,9 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 	}
 	trd.Key = ch.Key
 	tr.Set(trd, hb)
-	white = whitelist.Contains(ch.Mid)
+	atrd := s.startAck(ch, tr, conn)
+	wver := whitelist.Version()
+	white = whitelist.Match(ch.Mid, ch.Key)
 	if white {
 		whitelist.Printf("key: %s[%s] auth\n", ch.Key, rid)
 	}
@@ -230,6 +251,7 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 	go s.dispatchWebsocket(ws, wp, wb, ch)
 	serverHeartbeat := s.RandServerHearbeat()
 	for {
+		white = whitelist.Refresh(&wver, ch.Mid, ch.Key, white)
 		if p, err = ch.CliProto.Set(); err != nil {
 			break
 		}
@@ -239,10 +261,17 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 		if err = p.ReadWebsocket(ws); err != nil {
 			break
 		}
+		metricReads.WithLabelValues(protoWebsocket, metricOp(p.Op)).Inc()
 		if white {
 			whitelist.Printf("key: %s read proto:%v\n", ch.Key, p)
 		}
+		if p.Op == protocol.OpPushMsgAck {
+			//*确认消息不需要回复,槽位留给下一条
+			s.Ack(ch, p.Seq)
+			continue
+		}
 		if p.Op == protocol.OpHeartbeat {
+			metricHeartbeats.WithLabelValues(protoWebsocket).Inc()
 			tr.Set(trd, hb)
 			p.Op = protocol.OpHeartbeatReply
 			p.Body = nil
@@ -278,12 +307,14 @@ func (s *Server) ServeWebsocket(conn net.Conn, rp, wp *bytes.Pool, tr *xtime.Tim
 	}
 	b.Del(ch)
 	tr.Del(trd)
+	unacked := s.stopAck(ch, tr, atrd)
 	ws.Close()
 	ch.Close()
 	rp.Put(rb)
 	if err = s.Disconnect(ctx, ch.Mid, ch.Key); err != nil {
 		log.Errorf("key: %s operator do disconnect error(%v)", ch.Key, err)
 	}
+	_ = s.Unacked(ctx, ch.Mid, ch.Key, unacked)
 	if white {
 		whitelist.Printf("key: %s disconnect error(%v)\n", ch.Key, err)
 	}
@@ -297,12 +328,14 @@ func (s *Server) dispatchWebsocket(ws *websocket.Conn, wp *bytes.Pool, wb *bytes
 		err    error
 		finish bool
 		online int32
-		white  = whitelist.Contains(ch.Mid)
+		wver   = whitelist.Version()
+		white  = whitelist.Match(ch.Mid, ch.Key)
 	)
 	if conf.Conf.Debug {
 		log.Infof("key: %s start dispatch tcp goroutine", ch.Key)
 	}
 	for {
+		white = whitelist.Refresh(&wver, ch.Mid, ch.Key, white)
 		if white {
 			whitelist.Printf("key: %s wait proto ready\n", ch.Key)
 		}
@@ -333,8 +366,8 @@ func (s *Server) dispatchWebsocket(ws *websocket.Conn, wp *bytes.Pool, wb *bytes
 					whitelist.Printf("key: %s start write client proto%v\n", ch.Key, p)
 				}
 				if p.Op == protocol.OpHeartbeatReply {
-					if ch.Room != nil {
-						online = ch.Room.OnlineNum()
+					if room := ch.Room(); room != nil {
+						online = room.OnlineNum()
 					}
 					if err = p.WriteWebsocketHeart(ws, online); err != nil {
 						goto failed
@@ -344,6 +377,7 @@ func (s *Server) dispatchWebsocket(ws *websocket.Conn, wp *bytes.Pool, wb *bytes
 						goto failed
 					}
 				}
+				metricWrites.WithLabelValues(protoWebsocket, metricOp(p.Op)).Inc()
 				if white {
 					whitelist.Printf("key: %s write client proto%v\n", ch.Key, p)
 				}
@@ -357,6 +391,7 @@ func (s *Server) dispatchWebsocket(ws *websocket.Conn, wp *bytes.Pool, wb *bytes
 			if err = p.WriteWebsocket(ws); err != nil {
 				goto failed
 			}
+			metricWrites.WithLabelValues(protoWebsocket, metricOp(p.Op)).Inc()
 			if white {
 				whitelist.Printf("key: %s write server proto%v\n", ch.Key, p)
 			}
@@ -367,7 +402,10 @@ func (s *Server) dispatchWebsocket(ws *websocket.Conn, wp *bytes.Pool, wb *bytes
 		if white {
 			whitelist.Printf("key: %s start flush \n", ch.Key)
 		}
-		if err = ws.Flush(); err != nil {
+		start := time.Now()
+		err = ws.Flush()
+		metricFlushDuration.WithLabelValues(protoWebsocket).Observe(time.Since(start).Seconds())
+		if err != nil {
 			break
 		}
 		if white {
@@ -393,7 +431,7 @@ failed:
 }
 
 
-func (s *Server) authWebsocket(ctx context.Context, ws *websocket.Conn, p *protocol.Proto, cookie string) (mid int64, key, rid string, accepts []int32, hb time.Duration, err error) {
+func (s *Server) authWebsocket(ctx context.Context, ws *websocket.Conn, p *protocol.Proto, cookie, ip string) (mid int64, key, rid string, accepts []int32, hb time.Duration, err error) {
 	for {
 		if err = p.ReadWebsocket(ws); err != nil {
 			return
@@ -404,7 +442,7 @@ func (s *Server) authWebsocket(ctx context.Context, ws *websocket.Conn, p *proto
 			log.Errorf("ws request operation(%d) not auth", p.Op)
 		}
 	}
-	if mid, key, rid, accepts, hb, err = s.Connect(ctx, p, cookie); err != nil {
+	if mid, key, rid, accepts, hb, err = s.Connect(ctx, p, cookie, ip); err != nil {
 		return
 	}
 	p.Op = protocol.OpAuthReply
@@ -0,0 +1,35 @@
+package comet
+
+import (
+	"encoding/json"
+	"math/rand"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+	"go.uber.org/zap"
+)
+
+// *把最多count个连接转移到其他comet: 发送带目标节点的OpRedirect后关闭连接,返回实际转移的连接数。
+// *从随机的bucket开始挑选,避免每次都转移同一批用户;正在优雅关闭时不处理
+func (s *Server) Shed(count int, server string, addrs []string) (n int) {
+	if count <= 0 || s.Draining() {
+		return
+	}
+	body, _ := json.Marshal(map[string]interface{}{"server": server, "addrs": addrs})
+	start := rand.Intn(len(s.buckets))
+	for i := 0; i < len(s.buckets) && n < count; i++ {
+		for _, ch := range s.buckets[(start+i)%len(s.buckets)].Channels() {
+			//*发送协程卡住时直接断开连接,客户端收不到目标节点,按自身的退避策略重连
+			_ = ch.Push(&protocol.Proto{Ver: 1, Op: protocol.OpRedirect, Body: body})
+			ch.Shutdown()
+			if n++; n >= count {
+				break
+			}
+		}
+	}
+	logger.Info("shed channels",
+		zap.Int("count", n),
+		zap.String("server", server),
+		zap.Strings("addrs", addrs),
+	)
+	return
+}
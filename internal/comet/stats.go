@@ -0,0 +1,64 @@
+package comet
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// *推送缓冲已满被丢弃的消息数
+var signalDropped uint64
+
+// *返回推送缓冲已满被丢弃的消息数
+func SignalDropped() uint64 {
+	return atomic.LoadUint64(&signalDropped)
+}
+
+// *单个bucket的统计
+type BucketStats struct {
+	Channels int //*连接数
+	Rooms    int //*有在线连接的房间数
+	IPs      int //*不同的IP数
+}
+
+// *本机的连接、房间和运行时统计
+type Stats struct {
+	Buckets       []*BucketStats //*每个bucket的统计
+	Channels      int            //*连接总数
+	Rooms         int            //*有在线连接的房间总数
+	IPs           int            //*不同的IP总数
+	SignalDropped uint64         //*信号通道已满被丢弃的消息数
+	Goroutines    int            //*goroutine数量
+	HeapAlloc     uint64         //*堆上已分配的字节数
+	HeapInuse     uint64         //*堆上正在使用的字节数
+	Sys           uint64         //*从系统申请的字节数
+	NumGC         uint32         //*GC次数
+	StartTime     int64          //*启动时间，使用 Unix 时间戳表示
+}
+
+// *统计本机的连接、房间和运行时状态
+func (s *Server) Stats() *Stats {
+	var (
+		st    = &Stats{Buckets: make([]*BucketStats, 0, len(s.buckets))}
+		rooms = make(map[string]struct{})
+		ips   = make(map[string]struct{})
+		mem   runtime.MemStats
+	)
+	for _, b := range s.buckets {
+		bRooms, bIPs := b.Rooms(), b.IPCount()
+		st.Buckets = append(st.Buckets, &BucketStats{Channels: b.ChannelCount(), Rooms: len(bRooms), IPs: len(bIPs)})
+		st.Channels += b.ChannelCount()
+		for room := range bRooms {
+			rooms[room] = struct{}{}
+		}
+		for ip := range bIPs {
+			ips[ip] = struct{}{}
+		}
+	}
+	st.Rooms, st.IPs = len(rooms), len(ips)
+	runtime.ReadMemStats(&mem)
+	st.SignalDropped = SignalDropped()
+	st.Goroutines = runtime.NumGoroutine()
+	st.HeapAlloc, st.HeapInuse, st.Sys, st.NumGC = mem.HeapAlloc, mem.HeapInuse, mem.Sys, mem.NumGC
+	st.StartTime = s.started.Unix()
+	return st
+}
@@ -3,14 +3,40 @@ package comet
 import (
 	"log"
 	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/gyy0727/mygoim/internal/comet/conf"
+	"github.com/gyy0727/mygoim/internal/comet/errors"
+	"github.com/zhenjl/cityhash"
 )
 
 var whitelist *Whitelist
 
 type Whitelist struct {
-	log  *log.Logger
-	list map[int64]struct{}
+	log      *log.Logger
+	mutex    sync.RWMutex
+	list     map[int64]time.Time     //*白名单用户及过期时间,零值表示不过期
+	prefixes map[string]*whitePrefix //*按key前缀采样的白名单
+	ver      uint64                  //*白名单版本,每次变更加一,连接据此重新判断是否需要追踪
+}
+
+const whiteGCTick = time.Minute //*清理过期白名单项的间隔
+
+// *按key前缀采样的白名单项
+type whitePrefix struct {
+	sample uint32    //*采样百分比,1-100
+	expire time.Time //*过期时间,零值表示不过期
+}
+
+// *白名单项,用于管理接口展示
+type WhiteEntry struct {
+	Mid    int64     `json:"mid,omitempty"`
+	Prefix string    `json:"prefix,omitempty"`
+	Sample int       `json:"sample,omitempty"`
+	Expire time.Time `json:"expire"`
 }
 
 //*初始化,将config结构体的白名单数据加载到当前Whitelsit结构体 
@@ -23,19 +49,184 @@ func InitWhitelist(c *conf.Whitelist) (err error) {
 	if f, err = os.OpenFile(c.WhiteLog, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644); err == nil {
 		whitelist = new(Whitelist)
 		whitelist.log = log.New(f, "", log.LstdFlags)
-		whitelist.list = make(map[int64]struct{})
+		whitelist.list = make(map[int64]time.Time)
+		whitelist.prefixes = make(map[string]*whitePrefix)
 		for _, mid = range c.Whitelist {
-			whitelist.list[mid] = struct{}{}
+			whitelist.list[mid] = time.Time{}
 		}
+		go whitelist.gcproc()
 	}
 	return
 }
 
+// *定期清理过期的白名单项
+func (w *Whitelist) gcproc() {
+	for {
+		time.Sleep(whiteGCTick)
+		w.mutex.Lock()
+		w.gc()
+		w.mutex.Unlock()
+	}
+}
+
+// *返回白名单版本
+func (w *Whitelist) Version() uint64 {
+	return atomic.LoadUint64(&w.ver)
+}
+
+// *白名单有变更时重新判断连接是否需要追踪,ver为连接上次判断时的版本
+func (w *Whitelist) Refresh(ver *uint64, mid int64, key string, white bool) bool {
+	if v := w.Version(); v != *ver {
+		*ver = v
+		return w.Match(mid, key)
+	}
+	return white
+}
+
+// *判断是否已过期
+func whiteExpired(expire, now time.Time) bool {
+	return !expire.IsZero() && now.After(expire)
+}
+
+// *ttl<=0时不过期
+func whiteExpire(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
 //*判断是否包含当前用户 
 func (w *Whitelist) Contains(mid int64) (ok bool) {
-	if mid > 0 {
-		_, ok = w.list[mid]
+	if mid <= 0 {
+		return
+	}
+	w.mutex.RLock()
+	expire, ok := w.list[mid]
+	w.mutex.RUnlock()
+	return ok && !whiteExpired(expire, time.Now())
+}
+
+// *判断连接是否需要追踪: 用户在白名单中,或key匹配前缀且命中采样,同一个key的采样结果固定
+func (w *Whitelist) Match(mid int64, key string) bool {
+	if w.Contains(mid) {
+		return true
 	}
+	now := time.Now()
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	for prefix, p := range w.prefixes {
+		if len(key) < len(prefix) || key[:len(prefix)] != prefix || whiteExpired(p.expire, now) {
+			continue
+		}
+		if cityhash.CityHash32([]byte(key), uint32(len(key)))%100 < p.sample {
+			return true
+		}
+	}
+	return false
+}
+
+// *添加白名单用户,ttl<=0时不过期
+func (w *Whitelist) Add(mid int64, ttl time.Duration) {
+	w.mutex.Lock()
+	w.list[mid] = whiteExpire(ttl)
+	w.gc()
+	atomic.AddUint64(&w.ver, 1)
+	w.mutex.Unlock()
+}
+
+// *移除白名单用户
+func (w *Whitelist) Del(mid int64) {
+	w.mutex.Lock()
+	delete(w.list, mid)
+	atomic.AddUint64(&w.ver, 1)
+	w.mutex.Unlock()
+}
+
+// *添加key前缀,sample为采样百分比,不在1-100之间时返回错误
+func (w *Whitelist) AddPrefix(prefix string, sample int, ttl time.Duration) error {
+	if sample < 1 || sample > 100 {
+		return errors.ErrWhitelistArg
+	}
+	w.mutex.Lock()
+	w.prefixes[prefix] = &whitePrefix{sample: uint32(sample), expire: whiteExpire(ttl)}
+	w.gc()
+	atomic.AddUint64(&w.ver, 1)
+	w.mutex.Unlock()
+	return nil
+}
+
+// *移除key前缀
+func (w *Whitelist) DelPrefix(prefix string) {
+	w.mutex.Lock()
+	delete(w.prefixes, prefix)
+	atomic.AddUint64(&w.ver, 1)
+	w.mutex.Unlock()
+}
+
+// *按配置文件重新加载白名单: 移除旧配置中有而新配置中没有的用户,添加新配置中的用户,运行时添加的不受影响
+func (w *Whitelist) Reload(olds, news []int64) {
+	keep := make(map[int64]struct{}, len(news))
+	for _, mid := range news {
+		keep[mid] = struct{}{}
+	}
+	w.mutex.Lock()
+	for _, mid := range olds {
+		if _, ok := keep[mid]; !ok {
+			delete(w.list, mid)
+		}
+	}
+	for _, mid := range news {
+		w.list[mid] = time.Time{}
+	}
+	atomic.AddUint64(&w.ver, 1)
+	w.mutex.Unlock()
+}
+
+// *清理过期的白名单项,有清理时版本加一,调用方需持有写锁
+func (w *Whitelist) gc() {
+	var (
+		now     = time.Now()
+		removed bool
+	)
+	for mid, expire := range w.list {
+		if whiteExpired(expire, now) {
+			delete(w.list, mid)
+			removed = true
+		}
+	}
+	for prefix, p := range w.prefixes {
+		if whiteExpired(p.expire, now) {
+			delete(w.prefixes, prefix)
+			removed = true
+		}
+	}
+	if removed {
+		atomic.AddUint64(&w.ver, 1)
+	}
+}
+
+//*返回白名单中未过期的用户和前缀
+func (w *Whitelist) List() (entries []*WhiteEntry) {
+	now := time.Now()
+	w.mutex.RLock()
+	for mid, expire := range w.list {
+		if !whiteExpired(expire, now) {
+			entries = append(entries, &WhiteEntry{Mid: mid, Expire: expire})
+		}
+	}
+	for prefix, p := range w.prefixes {
+		if !whiteExpired(p.expire, now) {
+			entries = append(entries, &WhiteEntry{Prefix: prefix, Sample: int(p.sample), Expire: p.expire})
+		}
+	}
+	w.mutex.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Mid != entries[j].Mid {
+			return entries[i].Mid < entries[j].Mid
+		}
+		return entries[i].Prefix < entries[j].Prefix
+	})
 	return
 }
 
@@ -43,3 +234,34 @@ func (w *Whitelist) Contains(mid int64) (ok bool) {
 func (w *Whitelist) Printf(format string, v ...interface{}) {
 	w.log.Printf(format, v...)
 }
+
+// *运行时添加白名单,已建立的连接在下一条消息时生效
+func (s *Server) AddWhitelist(mids []int64, prefixes []string, sample int, ttl time.Duration) error {
+	if len(mids) == 0 && len(prefixes) == 0 {
+		return errors.ErrWhitelistArg
+	}
+	if len(prefixes) > 0 && (sample < 1 || sample > 100) {
+		return errors.ErrWhitelistArg
+	}
+	for _, mid := range mids {
+		whitelist.Add(mid, ttl)
+	}
+	for _, prefix := range prefixes {
+		_ = whitelist.AddPrefix(prefix, sample, ttl)
+	}
+	return nil
+}
+
+// *运行时移除白名单
+func (s *Server) DelWhitelist(mids []int64, prefixes []string) error {
+	if len(mids) == 0 && len(prefixes) == 0 {
+		return errors.ErrWhitelistArg
+	}
+	for _, mid := range mids {
+		whitelist.Del(mid)
+	}
+	for _, prefix := range prefixes {
+		whitelist.DelPrefix(prefix)
+	}
+	return nil
+}
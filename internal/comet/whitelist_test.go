@@ -0,0 +1,68 @@
+package comet
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestWhitelist() *Whitelist {
+	return &Whitelist{list: make(map[int64]time.Time), prefixes: make(map[string]*whitePrefix)}
+}
+
+// 测试运行时增删白名单用户和过期
+func TestWhitelistTTL(t *testing.T) {
+	w := newTestWhitelist()
+	w.Add(1, 0)
+	w.Add(2, time.Millisecond)
+	if !w.Contains(1) || !w.Contains(2) {
+		t.Fatal("added mids not contained")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if w.Contains(2) {
+		t.Fatal("expired mid still contained")
+	}
+	ver := w.Version()
+	w.Del(1)
+	if w.Contains(1) || w.Version() == ver {
+		t.Fatal("deleted mid still contained or version not changed")
+	}
+	if entries := w.List(); len(entries) != 0 {
+		t.Fatalf("entries %v; want empty", entries)
+	}
+}
+
+// 测试按key前缀采样,同一个key的结果固定
+func TestWhitelistPrefixSample(t *testing.T) {
+	w := newTestWhitelist()
+	w.AddPrefix("web-", 30, 0)
+	var hit int
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("web-%d", i)
+		m := w.Match(0, key)
+		if m != w.Match(0, key) {
+			t.Fatalf("sample of %s not stable", key)
+		}
+		if m {
+			hit++
+		}
+	}
+	if hit < 200 || hit > 400 {
+		t.Fatalf("sampled %d of 1000; want about 300", hit)
+	}
+	if w.Match(0, "ios-1") {
+		t.Fatal("key without prefix matched")
+	}
+	for _, sample := range []int{-1, 0, 101} {
+		if err := w.AddPrefix("ios-", sample, 0); err == nil {
+			t.Fatalf("sample %d accepted", sample)
+		}
+	}
+	if w.Match(0, "ios-1") {
+		t.Fatal("rejected prefix matched")
+	}
+	w.AddPrefix("ios-", 100, 0)
+	if !w.Match(0, "ios-1") {
+		t.Fatal("full sample prefix not matched")
+	}
+}
@@ -3,6 +3,8 @@ package job
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -54,6 +56,7 @@ func newCometClient(addr string) (comet.CometClient, error) {
 
 type Comet struct {
 	serverID      string                         //*服务器id
+	addr          string                         //*comet的grpc地址
 	client        comet.CometClient              //*连接comet层的rpc客户端
 	pushChan      []chan *comet.PushMsgReq       //*推送用户消息的管道
 	roomChan      []chan *comet.BroadcastRoomReq //*推送房间消息
@@ -61,27 +64,34 @@ type Comet struct {
 	pushChanNum   uint64                         //*推送用户消息管道数量
 	roomChanNum   uint64                         //*推送房间消息管道数量
 	routineSize   uint64                         //*协程数量
+	closeTimeout  time.Duration                  //*关闭时等待推送完成的最长时间
+	pending       sync.WaitGroup                 //*排队和发送中的消息
+	closeMutex    sync.RWMutex                   //*closed的锁,保证关闭后不再增加pending
+	closed        bool                           //*是否已关闭
 	ctx           context.Context                //*上下文
 	cancel        context.CancelFunc             //*上下文取消函数
 }
 
-func NewComet(addr string, c *conf.Comet) (*Comet, error) {
-	fmt.Println("addr:", addr)
-	
+// *新建一个Comet客户端,addr 可以是 host:port 或 grpc://host:port
+func NewComet(serverID, addr string, c *conf.Comet) (*Comet, error) {
 	cmt := &Comet{
-		serverID:      addr,
+		serverID:      serverID,
+		addr:          addr,
 		pushChan:      make([]chan *comet.PushMsgReq, c.RoutineSize),
 		roomChan:      make([]chan *comet.BroadcastRoomReq, c.RoutineSize),
 		broadcastChan: make(chan *comet.BroadcastReq, c.RoutineSize),
 		routineSize:   uint64(c.RoutineSize),
+		closeTimeout:  time.Duration(c.CloseTimeout),
+	}
+	grpcAddr := addr
+	if u, err := url.Parse(addr); err == nil && u.Scheme == "grpc" {
+		grpcAddr = u.Host
 	}
-	var grpcAddr string
-
 	if grpcAddr == "" {
 		return nil, fmt.Errorf("invalid grpc address:%v", addr)
 	}
 	var err error
-	if cmt.client, err = newCometClient(addr); err != nil {
+	if cmt.client, err = newCometClient(grpcAddr); err != nil {
 		return nil, err
 	}
 	cmt.ctx, cmt.cancel = context.WithCancel(context.Background())
@@ -94,23 +104,96 @@ func NewComet(addr string, c *conf.Comet) (*Comet, error) {
 	return cmt, nil
 }
 
-// Push push a user message.
+// Push push a user message, block until queued or the comet is closed.
 func (c *Comet) Push(arg *comet.PushMsgReq) (err error) {
 	idx := atomic.AddUint64(&c.pushChanNum, 1) % c.routineSize
-	c.pushChan[idx] <- arg
+	if !c.acquire() {
+		err = ErrComet
+		observeDrop(err)
+		return
+	}
+	//*先尝试不阻塞地放入,管道满时记一次再阻塞等待
+	select {
+	case c.pushChan[idx] <- arg:
+		return
+	default:
+		observeFull(c.serverID, "Push")
+	}
+	select {
+	case c.pushChan[idx] <- arg:
+	case <-c.ctx.Done():
+		c.pending.Done()
+		err = ErrComet
+		observeDrop(err)
+	}
 	return
 }
 
-// BroadcastRoom broadcast a room message.
+// BroadcastRoom broadcast a room message, block until queued or the comet is closed.
 func (c *Comet) BroadcastRoom(arg *comet.BroadcastRoomReq) (err error) {
 	idx := atomic.AddUint64(&c.roomChanNum, 1) % c.routineSize
-	c.roomChan[idx] <- arg
+	if !c.acquire() {
+		err = ErrComet
+		observeDrop(err)
+		return
+	}
+	//*先尝试不阻塞地放入,管道满时记一次再阻塞等待
+	select {
+	case c.roomChan[idx] <- arg:
+		return
+	default:
+		observeFull(c.serverID, "BroadcastRoom")
+	}
+	select {
+	case c.roomChan[idx] <- arg:
+	case <-c.ctx.Done():
+		c.pending.Done()
+		err = ErrComet
+		observeDrop(err)
+	}
 	return
 }
 
-// Broadcast broadcast a message.
+// Broadcast broadcast a message, block until queued or the comet is closed.
 func (c *Comet) Broadcast(arg *comet.BroadcastReq) (err error) {
-	c.broadcastChan <- arg
+	if !c.acquire() {
+		err = ErrComet
+		observeDrop(err)
+		return
+	}
+	//*先尝试不阻塞地放入,管道满时记一次再阻塞等待
+	select {
+	case c.broadcastChan <- arg:
+		return
+	default:
+		observeFull(c.serverID, "Broadcast")
+	}
+	select {
+	case c.broadcastChan <- arg:
+	case <-c.ctx.Done():
+		c.pending.Done()
+		err = ErrComet
+		observeDrop(err)
+	}
+	return
+}
+
+// acquire count a message as pending, return false if the comet is closed.
+func (c *Comet) acquire() bool {
+	c.closeMutex.RLock()
+	defer c.closeMutex.RUnlock()
+	if c.closed {
+		return false
+	}
+	c.pending.Add(1)
+	return true
+}
+
+// Kick close conns of the keys.
+func (c *Comet) Kick(arg *comet.KickReq) (err error) {
+	start := time.Now()
+	_, err = c.client.Kick(context.Background(), arg)
+	observePush(c.serverID, "Kick", start, err)
 	return
 }
 
@@ -118,28 +201,37 @@ func (c *Comet) process(pushChan chan *comet.PushMsgReq, roomChan chan *comet.Br
 	for {
 		select {
 		case broadcastArg := <-broadcastChan:
+			start := time.Now()
 			_, err := c.client.Broadcast(context.Background(), &comet.BroadcastReq{
 				Proto:   broadcastArg.Proto,
 				ProtoOp: broadcastArg.ProtoOp,
 				Speed:   broadcastArg.Speed,
 			})
+			observePush(c.serverID, "Broadcast", start, err)
+			c.pending.Done()
 			if err != nil {
 				log.Errorf("c.client.Broadcast(%s, reply) serverId:%s error(%v)", broadcastArg, c.serverID, err)
 			}
 		case roomArg := <-roomChan:
+			start := time.Now()
 			_, err := c.client.BroadcastRoom(context.Background(), &comet.BroadcastRoomReq{
 				RoomID: roomArg.RoomID,
 				Proto:  roomArg.Proto,
 			})
+			observePush(c.serverID, "BroadcastRoom", start, err)
+			c.pending.Done()
 			if err != nil {
 				log.Errorf("c.client.BroadcastRoom(%s, reply) serverId:%s error(%v)", roomArg, c.serverID, err)
 			}
 		case pushArg := <-pushChan:
+			start := time.Now()
 			_, err := c.client.PushMsg(context.Background(), &comet.PushMsgReq{
 				Keys:    pushArg.Keys,
 				Proto:   pushArg.Proto,
 				ProtoOp: pushArg.ProtoOp,
 			})
+			observePush(c.serverID, "PushMsg", start, err)
+			c.pending.Done()
 			if err != nil {
 				log.Errorf("c.client.PushMsg(%s, reply) serverId:%s error(%v)", pushArg, c.serverID, err)
 			}
@@ -149,31 +241,34 @@ func (c *Comet) process(pushChan chan *comet.PushMsgReq, roomChan chan *comet.Br
 	}
 }
 
-// Close close the resources.
+// Close wait for queued and in-flight pushes to finish, then stop the routines.
 func (c *Comet) Close() (err error) {
-	finish := make(chan bool)
+	c.closeMutex.Lock()
+	c.closed = true
+	c.closeMutex.Unlock()
+	finish := make(chan struct{})
 	go func() {
-		for {
-			n := len(c.broadcastChan)
-			for _, ch := range c.pushChan {
-				n += len(ch)
-			}
-			for _, ch := range c.roomChan {
-				n += len(ch)
-			}
-			if n == 0 {
-				finish <- true
-				return
-			}
-			time.Sleep(time.Second)
-		}
+		c.pending.Wait()
+		close(finish)
 	}()
 	select {
 	case <-finish:
 		log.Info("close comet finish")
-	case <-time.After(5 * time.Second):
-		err = fmt.Errorf("close comet(server:%s push:%d room:%d broadcast:%d) timeout", c.serverID, len(c.pushChan), len(c.roomChan), len(c.broadcastChan))
+	case <-time.After(c.closeTimeout):
+		err = fmt.Errorf("close comet(server:%s queued:%d) timeout", c.serverID, c.queued())
 	}
 	c.cancel()
 	return
 }
+
+// queued return the number of messages still in the chans.
+func (c *Comet) queued() int {
+	n := len(c.broadcastChan)
+	for _, ch := range c.pushChan {
+		n += len(ch)
+	}
+	for _, ch := range c.roomChan {
+		n += len(ch)
+	}
+	return n
+}
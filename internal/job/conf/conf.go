@@ -8,7 +8,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	xtime "github.com/gyy0727/mygoim/pkg/time"
-	"go.etcd.io/etcd/clientv3"
+	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
 var (
@@ -24,7 +24,7 @@ func init() {
 	var (
 		defHost, _ = os.Hostname()
 	)
-	flag.StringVar(&confPath, "conf", "job-example.toml", "default config path")
+	flag.StringVar(&confPath, "conf", "job.toml", "default config path")
 	flag.StringVar(&region, "region", os.Getenv("REGION"), "avaliable region. or use REGION env variable, value: sh etc.")
 	flag.StringVar(&zone, "zone", os.Getenv("ZONE"), "avaliable zone. or use ZONE env variable, value: sh001/sh002 etc.")
 	flag.StringVar(&deployEnv, "deploy.env", os.Getenv("DEPLOY_ENV"), "deploy env. or use DEPLOY_ENV env variable, value: dev/fat1/uat/pre/prod etc.")
@@ -63,12 +63,13 @@ func Default() *Config {
 			Endpoints:   []string{"127.0.0.1:2379"}, // 默认 etcd 地址
 			DialTimeout: time.Second * 5,            // 默认超时时间
 		},
-		Comet: &Comet{RoutineChan: 1024, RoutineSize: 32},
+		Comet: &Comet{RoutineChan: 1024, RoutineSize: 32, CloseTimeout: xtime.Duration(time.Second * 5)},
 		Room: &Room{
 			Batch:  20,
 			Signal: xtime.Duration(time.Second),
 			Idle:   xtime.Duration(time.Minute * 15),
 		},
+		Metrics: &Metrics{Open: false, Addr: ":3112", Path: "/metrics"},
 	}
 }
 
@@ -78,6 +79,7 @@ type Config struct {
 	Discovery *EtcdConfig // 改为 etcd 配置
 	Comet     *Comet
 	Room      *Room
+	Metrics   *Metrics
 }
 
 type EtcdConfig struct {
@@ -94,8 +96,16 @@ type Room struct {
 }
 
 type Comet struct {
-	RoutineChan int
-	RoutineSize int
+	RoutineChan  int
+	RoutineSize  int
+	CloseTimeout xtime.Duration // 关闭时等待排队和发送中的消息推送完成的最长时间
+}
+
+// *监控配置
+type Metrics struct {
+	Open bool   // 是否开启prometheus监控
+	Addr string // 监控http服务的监听地址
+	Path string // 监控数据的路径
 }
 
 type Kafka struct {
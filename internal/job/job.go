@@ -3,6 +3,8 @@ package job
 import (
 	"context"
 	"sync"
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	pb "github.com/gyy0727/mygoim/api/logic"
 	"github.com/gyy0727/mygoim/internal/job/conf"
@@ -12,13 +14,24 @@ import (
 )
 
 
+const (
+	_cometAppID     = "goim.comet" //*comet在服务发现中的名称
+	_watchCometTick = time.Second  //*检查comet节点变化的间隔
+)
+
 // *Job is push job.
 type Job struct {
 	c            *conf.Config
-	consumer     sarama.ConsumerGroup  //*消费者
-	cometServers map[string]*Comet //*连接comet层的rpc客户端
-	rooms        map[string]*Room  //*房间
-	roomsMutex   sync.RWMutex      //*房间锁
+	consumer     sarama.ConsumerGroup //*消费者
+	cometServers map[string]*Comet    //*连接comet层的rpc客户端,key为comet的serverID
+	cometsMutex  sync.RWMutex         //*cometServers的锁
+	cometsClosed bool                 //*comet已全部关闭,不再新建,由cometsMutex保护
+	rooms        map[string]*Room     //*房间
+	roomsMutex   sync.RWMutex         //*房间锁
+	ctx          context.Context      //*消费的上下文,取消后停止消费
+	cancel       context.CancelFunc   //*停止消费
+	done         chan struct{}        //*Consume 退出后关闭
+	closeOnce    sync.Once            //*保证房间和comet只被排空一次
 }
 
 //*新建一个job实例
@@ -27,7 +40,9 @@ func New(c *conf.Config) *Job {
 		c:        c,
 		consumer: newKafkaSub(c.Kafka),
 		rooms:    make(map[string]*Room),
+		done:     make(chan struct{}),
 	}
+	j.ctx, j.cancel = context.WithCancel(context.Background())
 	j.watchComet()
 	return j
 }
@@ -36,24 +51,38 @@ func (j *Job) Setup(sarama.ConsumerGroupSession) error {
 	return nil
 }
 
-func (j *Job) Cleanup(sarama.ConsumerGroupSession) error {
+// *会话结束时调用,如果是退出流程,先把房间的批量消息和comet的推送管道排空再提交offset
+func (j *Job) Cleanup(session sarama.ConsumerGroupSession) error {
+	if j.ctx.Err() != nil {
+		j.drain()
+	}
+	session.Commit()
 	return nil
 }
 
 func (j *Job) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for msg := range claim.Messages() {
-		pushMsg := new(pb.PushMsg)
-		if err := proto.Unmarshal(msg.Value, pushMsg); err != nil {
-			log.Errorf("proto.Unmarshal(%v) error(%v)", msg, err)
-			continue
-		}
-		if err := j.push(context.Background(), pushMsg); err != nil {
-			log.Errorf("j.push(%v) error(%v)", pushMsg, err)
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			observeLag(msg.Topic, msg.Partition, claim.HighWaterMarkOffset(), msg.Offset)
+			pushMsg := new(pb.PushMsg)
+			if err := proto.Unmarshal(msg.Value, pushMsg); err != nil {
+				log.Errorf("proto.Unmarshal(%v) error(%v)", msg, err)
+				session.MarkMessage(msg, "")
+				continue
+			}
+			if err := j.push(context.Background(), pushMsg); err != nil {
+				log.Errorf("j.push(%v) error(%v)", pushMsg, err)
+			}
+			log.Infof("consume: %s/%d/%d\t%s\t%+v", msg.Topic, msg.Partition, msg.Offset, msg.Key, pushMsg)
+			session.MarkMessage(msg, "") // 标记消息为已处理
+		case <-session.Context().Done():
+			return nil
 		}
-		log.Infof("consume: %s/%d/%d\t%s\t%+v", msg.Topic, msg.Partition, msg.Offset, msg.Key, pushMsg)
-		session.MarkMessage(msg, "") // 标记消息为已处理
 	}
-	return nil
 }
 
 
@@ -71,34 +100,142 @@ func newKafkaSub(c *conf.Kafka) sarama.ConsumerGroup {
 }
 
 
-//*关闭job
+//*关闭job: 停止消费,排空房间和comet管道并提交offset后关闭消费者
 func (j *Job) Close() error {
+	j.cancel()
+	<-j.done
+	j.drain()
 	if j.consumer != nil {
 		return j.consumer.Close()
 	}
 	return nil
 }
 
-//*从kafka消费数据
+//*从kafka消费数据,直到Close被调用
 func (j *Job) Consume() {
+	defer close(j.done)
 	for {
-		if err := j.consumer.Consume(context.Background(), []string{j.c.Kafka.Topic}, j); err != nil {
+		if err := j.consumer.Consume(j.ctx, []string{j.c.Kafka.Topic}, j); err != nil {
 			log.Errorf("consumer error(%v)", err)
 		}
+		if j.ctx.Err() != nil {
+			log.Info("consumer exit")
+			return
+		}
 	}
 }
 
+//*退出时先把房间里攒着的批量消息推给comet,再等comet的推送管道排空
+func (j *Job) drain() {
+	j.closeOnce.Do(func() {
+		j.closeRooms()
+		j.closeComets()
+	})
+}
+
+//*关闭所有comet客户端,等待各自的推送管道排空,之后发现的节点不再新建
+func (j *Job) closeComets() {
+	j.cometsMutex.Lock()
+	j.cometsClosed = true
+	comets := j.cometServers
+	j.cometServers = make(map[string]*Comet)
+	j.cometsMutex.Unlock()
+	for serverID, c := range comets {
+		if err := c.Close(); err != nil {
+			log.Errorf("c.Close() serverID:%s error(%v)", serverID, err)
+		}
+	}
+}
 
-func (j *Job) watchComet(){
-	discovery.EtcdResolverInit()
-	discovery.EResolver.SetTargetNode("goim/logic")
-	node := discovery.EResolver.GetServiceNodes("goim/logic")
+
+// *监听goim.comet服务节点,节点出现时新建Comet,节点消失时关闭Comet
+func (j *Job) watchComet() {
 	j.cometServers = make(map[string]*Comet) // 初始化 cometServers
-	for _,nd := range node{
-		comet ,err :=NewComet(nd.Addr, j.c.Comet)
-		if err!= nil{
-			panic(err)
+	discovery.EResolver.SetTargetNode(_cometAppID)
+	j.newAddress(discovery.EResolver.GetServiceNodes(_cometAppID))
+	go func() {
+		ticker := time.NewTicker(_watchCometTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				j.newAddress(discovery.EResolver.GetServiceNodes(_cometAppID))
+			case <-j.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// *根据最新的节点列表增删Comet客户端
+func (j *Job) newAddress(nodes []*discovery.Node) {
+	servers := make(map[string]*discovery.Node, len(nodes))
+	for _, nd := range nodes {
+		servers[cometServerID(nd)] = nd
+	}
+	var added, removed []*Comet
+	j.cometsMutex.Lock()
+	if j.cometsClosed {
+		j.cometsMutex.Unlock()
+		return
+	}
+	for serverID, nd := range servers {
+		if _, ok := j.cometServers[serverID]; ok {
+			continue
+		}
+		c, err := NewComet(serverID, nd.Addr, j.c.Comet)
+		if err != nil {
+			log.Errorf("NewComet(%s,%s) error(%v)", serverID, nd.Addr, err)
+			continue
 		}
-		j.cometServers[nd.Addr]=comet
+		j.cometServers[serverID] = c
+		added = append(added, c)
+	}
+	for serverID, c := range j.cometServers {
+		if _, ok := servers[serverID]; !ok {
+			delete(j.cometServers, serverID)
+			removed = append(removed, c)
+		}
+	}
+	total := len(j.cometServers)
+	j.cometsMutex.Unlock()
+	for _, c := range added {
+		log.Infof("comet added serverID:%s addr:%s comets:%d", c.serverID, c.addr, total)
+	}
+	//*关闭时需要等待管道排空,放到协程里避免阻塞监听
+	for _, c := range removed {
+		log.Infof("comet removed serverID:%s addr:%s comets:%d", c.serverID, c.addr, total)
+		go func(c *Comet) {
+			if err := c.Close(); err != nil {
+				log.Errorf("c.Close() serverID:%s error(%v)", c.serverID, err)
+			}
+		}(c)
 	}
-}
\ No newline at end of file
+}
+
+// *comet以主机名作为serverID写入logic的映射关系,没有主机名时退化为地址
+func cometServerID(nd *discovery.Node) string {
+	if host := nd.Metadata[discovery.MetaHostname]; host != "" {
+		return host
+	}
+	return nd.Addr
+}
+
+// *返回serverID对应的Comet
+func (j *Job) comet(serverID string) (c *Comet, ok bool) {
+	j.cometsMutex.RLock()
+	c, ok = j.cometServers[serverID]
+	j.cometsMutex.RUnlock()
+	return
+}
+
+// *返回当前所有Comet的快照
+func (j *Job) comets() map[string]*Comet {
+	j.cometsMutex.RLock()
+	comets := make(map[string]*Comet, len(j.cometServers))
+	for serverID, c := range j.cometServers {
+		comets[serverID] = c
+	}
+	j.cometsMutex.RUnlock()
+	return comets
+}
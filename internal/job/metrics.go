@@ -0,0 +1,121 @@
+package job
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/internal/job/conf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricNamespace = "goim"
+	metricSubsystem = "job"
+)
+
+var (
+	// *每个分区的消费延迟,即最新offset与已消费offset的差
+	metricConsumeLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "consume_lag",
+		Help:      "Messages behind the high water mark by partition.",
+	}, []string{"topic", "partition"})
+	// *调用comet推送的耗时
+	metricPushDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "comet_push_duration_seconds",
+		Help:      "Comet push RPC latency by server and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "method"})
+	// *调用comet推送失败的次数
+	metricPushErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "comet_push_errors_total",
+		Help:      "Failed comet push RPCs by server and method.",
+	}, []string{"server", "method"})
+	// *房间合并推送时每批的消息数
+	metricRoomBatch = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "room_batch_size",
+		Help:      "Protos merged into one room broadcast by pushproc.",
+		Buckets:   prometheus.LinearBuckets(1, 5, 10),
+	})
+	// *被丢弃的消息数,reason为room_full(房间管道已满)或comet_closed(comet已关闭)
+	metricDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "drops_total",
+		Help:      "Messages dropped because a room chan was full or the comet was closed.",
+	}, []string{"reason"})
+	// *推送时comet管道已满需要阻塞等待的次数
+	metricChanFull = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "comet_chan_full_total",
+		Help:      "Sends that found the comet chan full and had to block by server and method.",
+	}, []string{"server", "method"})
+)
+
+func init() {
+	prometheus.MustRegister(metricConsumeLag, metricPushDuration, metricPushErrors, metricRoomBatch, metricDrops, metricChanFull)
+}
+
+// *记录一次comet推送的耗时和错误
+func observePush(server, method string, start time.Time, err error) {
+	metricPushDuration.WithLabelValues(server, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metricPushErrors.WithLabelValues(server, method).Inc()
+	}
+}
+
+// *记录分区的消费延迟
+func observeLag(topic string, partition int32, highWaterMark, offset int64) {
+	lag := highWaterMark - offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	metricConsumeLag.WithLabelValues(topic, strconv.Itoa(int(partition))).Set(float64(lag))
+}
+
+// *记录被丢弃的消息
+func observeDrop(err error) {
+	switch err {
+	case ErrRoomFull:
+		metricDrops.WithLabelValues("room_full").Inc()
+	case ErrComet:
+		metricDrops.WithLabelValues("comet_closed").Inc()
+	}
+}
+
+// *记录一次comet管道已满导致的阻塞
+func observeFull(server, method string) {
+	metricChanFull.WithLabelValues(server, method).Inc()
+}
+
+// *开启监控时启动/metrics的http服务
+func InitMetrics(c *conf.Metrics) (err error) {
+	if !c.Open {
+		return
+	}
+	lis, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle(c.Path, promhttp.Handler())
+	log.Infof("start metrics listen addr:%s path:%s", c.Addr, c.Path)
+	go func() {
+		if err := http.Serve(lis, mux); err != nil {
+			log.Errorf("metrics server exit error(%v)", err)
+		}
+	}()
+	return
+}
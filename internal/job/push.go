@@ -15,11 +15,13 @@ import (
 func (j *Job) push(ctx context.Context, pushMsg *pb.PushMsg) (err error) {
 	switch pushMsg.Type {
 	case pb.PushMsg_PUSH:
-		err = j.pushKeys(pushMsg.Operation, pushMsg.Server, pushMsg.Keys, pushMsg.Msg)
+		err = j.pushKeys(pushMsg.Operation, pushMsg.Seq, pushMsg.Server, pushMsg.Keys, pushMsg.Msg)
 	case pb.PushMsg_ROOM:
 		err = j.getRoom(pushMsg.Room).Push(pushMsg.Operation, pushMsg.Msg)
 	case pb.PushMsg_BROADCAST:
 		err = j.broadcast(pushMsg.Operation, pushMsg.Msg, pushMsg.Speed)
+	case pb.PushMsg_KICK:
+		err = j.kick(pushMsg.Server, pushMsg.Keys, pushMsg.Reason)
 	default:
 		err = fmt.Errorf("no match push type: %s", pushMsg.Type)
 	}
@@ -27,11 +29,12 @@ func (j *Job) push(ctx context.Context, pushMsg *pb.PushMsg) (err error) {
 }
 
 //*通过key进行推送
-func (j *Job) pushKeys(operation int32, serverID string, subKeys []string, body []byte) (err error) {
+func (j *Job) pushKeys(operation, seq int32, serverID string, subKeys []string, body []byte) (err error) {
 	buf := bytes.NewWriterSize(len(body) + 64)
 	p := &protocol.Proto{
 		Ver:  1,
 		Op:   operation,
+		Seq:  seq,
 		Body: body,
 	}
 	p.WriteTo(buf)
@@ -42,15 +45,28 @@ func (j *Job) pushKeys(operation int32, serverID string, subKeys []string, body
 		ProtoOp: operation,
 		Proto:   p,
 	}
-	if c, ok := j.cometServers[serverID]; ok {
+	if c, ok := j.comet(serverID); ok {
 		if err = c.Push(&args); err != nil {
 			log.Errorf("c.Push(%v) serverID:%s error(%v)", args, serverID, err)
 		}
-		log.Infof("pushKey:%s comets:%d", serverID, len(j.cometServers))
+		log.Infof("pushKey:%s", serverID)
 	}
 	return
 }
 
+//*踢掉指定comet上的连接
+func (j *Job) kick(serverID string, keys []string, reason int32) (err error) {
+	c, ok := j.comet(serverID)
+	if !ok {
+		return
+	}
+	if err = c.Kick(&comet.KickReq{Keys: keys, Reason: reason}); err != nil {
+		log.Errorf("c.Kick(%v) serverID:%s error(%v)", keys, serverID, err)
+	}
+	log.Infof("kick keys:%v serverID:%s reason:%d", keys, serverID, reason)
+	return
+}
+
 //*广播消息
 func (j *Job) broadcast(operation int32, body []byte, speed int32) (err error) {
 	buf := bytes.NewWriterSize(len(body) + 64)
@@ -62,7 +78,10 @@ func (j *Job) broadcast(operation int32, body []byte, speed int32) (err error) {
 	p.WriteTo(buf)
 	p.Body = buf.Buffer()
 	p.Op = protocol.OpRaw
-	comets := j.cometServers
+	comets := j.comets()
+	if len(comets) == 0 {
+		return
+	}
 	speed /= int32(len(comets))
 	var args = comet.BroadcastReq{
 		ProtoOp: operation,
@@ -88,7 +107,7 @@ func (j *Job) broadcastRoomRawBytes(roomID string, body []byte) (err error) {
 			Body: body,
 		},
 	}
-	comets := j.cometServers
+	comets := j.comets()
 	for serverID, c := range comets {
 		if err = c.BroadcastRoom(&args); err != nil {
 			log.Errorf("c.BroadcastRoom(%v) roomID:%s serverID:%s error(%v)", args, roomID, serverID, err)
@@ -13,8 +13,6 @@ import (
 var (
 	// ErrComet commet error.
 	ErrComet = errors.New("comet rpc is not available")
-	// ErrCometFull comet chan full.
-	ErrCometFull = errors.New("comet proto chan full")
 	// ErrRoomFull room chan full.
 	ErrRoomFull = errors.New("room proto chan full")
 
@@ -27,6 +25,7 @@ type Room struct {
 	job   *Job
 	id    string
 	proto chan *protocol.Proto
+	done  chan struct{} // closed when pushproc exit
 }
 
 // NewRoom new a room struct, store channel room info.
@@ -36,6 +35,7 @@ func NewRoom(job *Job, id string, c *conf.Room) (r *Room) {
 		id:    id,
 		job:   job,
 		proto: make(chan *protocol.Proto, c.Batch*2),
+		done:  make(chan struct{}),
 	}
 	go r.pushproc(c.Batch, time.Duration(c.Signal))
 	return
@@ -52,6 +52,7 @@ func (r *Room) Push(op int32, msg []byte) (err error) {
 	case r.proto <- p:
 	default:
 		err = ErrRoomFull
+		observeDrop(err)
 	}
 	return
 }
@@ -72,8 +73,14 @@ func (r *Room) pushproc(batch int, sigTime time.Duration) {
 		}
 	})
 	defer td.Stop()
+	defer close(r.done)
 	for {
 		if p = <-r.proto; p == nil {
+			// push the pending batch before exit
+			if n > 0 {
+				metricRoomBatch.Observe(float64(n))
+				_ = r.job.broadcastRoomRawBytes(r.id, buf.Buffer())
+			}
 			break // exit
 		} else if p != roomReadyProto {
 			// merge buffer ignore error, always nil
@@ -92,6 +99,7 @@ func (r *Room) pushproc(batch int, sigTime time.Duration) {
 				break
 			}
 		}
+		metricRoomBatch.Observe(float64(n))
 		_ = r.job.broadcastRoomRawBytes(r.id, buf.Buffer())
 
 		buf = bytes.NewWriterSize(buf.Size())
@@ -106,6 +114,28 @@ func (r *Room) pushproc(batch int, sigTime time.Duration) {
 	log.Infof("room:%s goroutine exit", r.id)
 }
 
+// Close flush the pending batch and wait pushproc exit.
+func (r *Room) Close() {
+	select {
+	case r.proto <- nil:
+	case <-r.done:
+	}
+	<-r.done
+}
+
+// closeRooms close all rooms and wait their pending batches pushed.
+func (j *Job) closeRooms() {
+	j.roomsMutex.RLock()
+	rooms := make([]*Room, 0, len(j.rooms))
+	for _, r := range j.rooms {
+		rooms = append(rooms, r)
+	}
+	j.roomsMutex.RUnlock()
+	for _, r := range rooms {
+		r.Close()
+	}
+}
+
 func (j *Job) delRoom(roomID string) {
 	j.roomsMutex.Lock()
 	delete(j.rooms, roomID)
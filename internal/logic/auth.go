@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/internal/logic/dao"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+var (
+	//*token格式错误或签名校验失败
+	ErrTokenInvalid = errors.New("auth token invalid")
+	//*token已过期
+	ErrTokenExpired = errors.New("auth token expired")
+	//*cookie会话不存在
+	ErrSessionNotFound = errors.New("auth session not found")
+)
+
+// *Authenticator 校验连接携带的cookie和token,返回连接对应的用户信息
+type Authenticator interface {
+	Auth(c context.Context, cookie string, token []byte) (*model.AuthToken, error)
+}
+
+// *根据配置选择认证方式,json不校验token,必须显式开启Insecure才能使用
+func NewAuthenticator(c *conf.Auth, d *dao.Dao) (Authenticator, error) {
+	if c == nil {
+		return nil, fmt.Errorf("auth config is empty")
+	}
+	switch c.Type {
+	case "json":
+		if !c.Insecure {
+			return nil, fmt.Errorf("auth type json lets any client claim any mid, set auth.insecure = true to use it")
+		}
+		return jsonAuth{}, nil
+	case "jwt":
+		return newJWTAuth(c.JWT)
+	case "hmac":
+		if c.HMAC == nil || c.HMAC.Secret == "" {
+			return nil, fmt.Errorf("auth hmac secret is empty")
+		}
+		return &hmacAuth{c: c.HMAC}, nil
+	case "cookie":
+		return &cookieAuth{c: c.Cookie, dao: d}, nil
+	}
+	return nil, fmt.Errorf("unknown auth type:%s", c.Type)
+}
+
+// *直接解析json格式的token,不做任何校验,只用于开发环境
+type jsonAuth struct{}
+
+func (jsonAuth) Auth(c context.Context, cookie string, token []byte) (t *model.AuthToken, err error) {
+	t = new(model.AuthToken)
+	if err = json.Unmarshal(token, t); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// *hmac签名的token: base64url(json).base64url(hmac-sha256(base64url(json)))
+type hmacAuth struct {
+	c *conf.HMACAuth
+}
+
+func (a *hmacAuth) Auth(c context.Context, cookie string, token []byte) (t *model.AuthToken, err error) {
+	i := strings.LastIndexByte(string(token), '.')
+	if i <= 0 {
+		return nil, ErrTokenInvalid
+	}
+	payload, sig := token[:i], token[i+1:]
+	mac := hmac.New(sha256.New, []byte(a.c.Secret))
+	mac.Write(payload)
+	want := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal(sig, []byte(want)) {
+		return nil, ErrTokenInvalid
+	}
+	b, err := base64.RawURLEncoding.DecodeString(string(payload))
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	t = new(model.AuthToken)
+	if err = json.Unmarshal(b, t); err != nil {
+		return nil, ErrTokenInvalid
+	}
+	if a.c.MaxAge > 0 && time.Since(time.Unix(t.Ts, 0)) > time.Duration(a.c.MaxAge) {
+		return nil, ErrTokenExpired
+	}
+	return
+}
+
+// *从cookie中取出会话ID,在redis中查找会话对应的用户,token中的房间、平台等信息仍然有效
+type cookieAuth struct {
+	c   *conf.CookieAuth
+	dao *dao.Dao
+}
+
+func (a *cookieAuth) Auth(c context.Context, cookie string, token []byte) (t *model.AuthToken, err error) {
+	req := http.Request{Header: http.Header{"Cookie": {cookie}}}
+	ck, err := req.Cookie(a.c.Name)
+	if err != nil || ck.Value == "" {
+		return nil, ErrSessionNotFound
+	}
+	session, err := a.dao.Session(c, ck.Value)
+	if err != nil {
+		return
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	t = new(model.AuthToken)
+	if len(token) > 0 {
+		if err = json.Unmarshal(token, t); err != nil {
+			return nil, err
+		}
+	}
+	//*用户ID只能来自会话,key也不允许客户端指定,防止顶掉别人的连接
+	t.Mid = session.Mid
+	t.Key = ""
+	if session.Platform != "" {
+		t.Platform = session.Platform
+	}
+	return
+}
@@ -0,0 +1,184 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *校验HS256或RS256签名的jwt,claim按配置映射到用户信息
+type jwtAuth struct {
+	c      *conf.JWTAuth
+	secret []byte
+	pub    *rsa.PublicKey
+}
+
+func newJWTAuth(c *conf.JWTAuth) (a *jwtAuth, err error) {
+	if c == nil {
+		return nil, fmt.Errorf("auth jwt config is empty")
+	}
+	a = &jwtAuth{c: c}
+	switch c.Alg {
+	case "HS256":
+		if c.Secret == "" {
+			return nil, fmt.Errorf("auth jwt secret is empty")
+		}
+		a.secret = []byte(c.Secret)
+	case "RS256":
+		if a.pub, err = loadRSAPublicKey(c.PublicKey); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported jwt alg:%s", c.Alg)
+	}
+	return
+}
+
+// *读取PEM格式的RSA公钥
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no pem block in %s", path)
+	}
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+			return rsaPub, nil
+		}
+		return nil, fmt.Errorf("%s is not a rsa public key", path)
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
+
+func (a *jwtAuth) Auth(c context.Context, cookie string, token []byte) (t *model.AuthToken, err error) {
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenInvalid
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err = decodeSegment(parts[0], &header); err != nil || header.Alg != a.c.Alg {
+		//*只接受配置的算法,防止算法混淆攻击
+		return nil, ErrTokenInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	if !a.verify(parts[0]+"."+parts[1], sig) {
+		return nil, ErrTokenInvalid
+	}
+	var claims map[string]interface{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrTokenInvalid
+	}
+	if err = a.validate(claims); err != nil {
+		return nil, err
+	}
+	t = new(model.AuthToken)
+	if t.Mid, err = claimInt64(claims[a.c.MidClaim]); err != nil {
+		return nil, ErrTokenInvalid
+	}
+	t.RoomID, _ = claims[a.c.RoomClaim].(string)
+	t.Platform, _ = claims[a.c.PlatformClaim].(string)
+	if accepts, ok := claims[a.c.AcceptsClaim].([]interface{}); ok {
+		for _, v := range accepts {
+			op, err := claimInt64(v)
+			if err != nil {
+				return nil, ErrTokenInvalid
+			}
+			t.Accepts = append(t.Accepts, int32(op))
+		}
+	}
+	return
+}
+
+// *校验签名
+func (a *jwtAuth) verify(signed string, sig []byte) bool {
+	switch a.c.Alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, a.secret)
+		mac.Write([]byte(signed))
+		return hmac.Equal(sig, mac.Sum(nil))
+	case "RS256":
+		h := sha256.Sum256([]byte(signed))
+		return rsa.VerifyPKCS1v15(a.pub, crypto.SHA256, h[:], sig) == nil
+	}
+	return false
+}
+
+// *校验过期时间、生效时间和签发者,过期时间必须有
+func (a *jwtAuth) validate(claims map[string]interface{}) error {
+	var (
+		now    = time.Now()
+		leeway = time.Duration(a.c.Leeway)
+	)
+	//*没有过期时间的token永久有效,直接拒绝
+	v, ok := claims["exp"]
+	if !ok {
+		return ErrTokenInvalid
+	}
+	exp, err := claimInt64(v)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+	if now.After(time.Unix(exp, 0).Add(leeway)) {
+		return ErrTokenExpired
+	}
+	if v, ok := claims["nbf"]; ok {
+		nbf, err := claimInt64(v)
+		if err != nil {
+			return ErrTokenInvalid
+		}
+		if now.Add(leeway).Before(time.Unix(nbf, 0)) {
+			return ErrTokenInvalid
+		}
+	}
+	if a.c.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.c.Issuer {
+			return ErrTokenInvalid
+		}
+	}
+	return nil
+}
+
+// *解码jwt的一段,数字保留为json.Number
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// *claim中的整数可能是数字也可能是字符串,例如sub
+func claimInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return strconv.ParseInt(n.String(), 10, 64)
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	}
+	return 0, fmt.Errorf("invalid claim:%v", v)
+}
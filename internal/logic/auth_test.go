@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	xtime "github.com/gyy0727/mygoim/pkg/time"
+)
+
+func signJWT(alg, claims string, sign func(string) []byte) string {
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString([]byte(`{"alg":"`+alg+`","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+	return signed + "." + enc.EncodeToString(sign(signed))
+}
+
+func TestJWTAuth(t *testing.T) {
+	c := conf.Default().Auth.JWT
+	c.Secret = "secret"
+	a, err := newJWTAuth(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs256 := func(key string) func(string) []byte {
+		return func(s string) []byte {
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write([]byte(s))
+			return mac.Sum(nil)
+		}
+	}
+	exp := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	token := signJWT("HS256", `{"mid":"123","room_id":"live://1","platform":"web","accepts":[1000,1001],"exp":`+exp+`}`, hs256("secret"))
+	res, err := a.Auth(context.Background(), "", []byte(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mid != 123 || res.RoomID != "live://1" || res.Platform != "web" || len(res.Accepts) != 2 || res.Accepts[1] != 1001 {
+		t.Fatalf("unexpected token:%+v", res)
+	}
+	if _, err = a.Auth(context.Background(), "", []byte(signJWT("HS256", `{"mid":1}`, hs256("other")))); err != ErrTokenInvalid {
+		t.Fatalf("bad signature error(%v)", err)
+	}
+	if _, err = a.Auth(context.Background(), "", []byte(signJWT("none", `{"mid":1}`, func(string) []byte { return nil }))); err != ErrTokenInvalid {
+		t.Fatalf("alg none error(%v)", err)
+	}
+	if _, err = a.Auth(context.Background(), "", []byte(signJWT("HS256", `{"mid":1,"exp":1}`, hs256("secret")))); err != ErrTokenExpired {
+		t.Fatalf("expired error(%v)", err)
+	}
+	if _, err = a.Auth(context.Background(), "", []byte(signJWT("HS256", `{"mid":1}`, hs256("secret")))); err != ErrTokenInvalid {
+		t.Fatalf("no exp error(%v)", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	path := filepath.Join(t.TempDir(), "pub.pem")
+	if err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c = conf.Default().Auth.JWT
+	c.Alg = "RS256"
+	c.PublicKey = path
+	c.MidClaim = "sub"
+	if a, err = newJWTAuth(c); err != nil {
+		t.Fatal(err)
+	}
+	token = signJWT("RS256", `{"sub":"456","exp":`+exp+`}`, func(s string) []byte {
+		h := sha256.Sum256([]byte(s))
+		sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+		return sig
+	})
+	if res, err = a.Auth(context.Background(), "", []byte(token)); err != nil || res.Mid != 456 {
+		t.Fatalf("rs256 token:%+v error(%v)", res, err)
+	}
+}
+
+func TestHMACAuth(t *testing.T) {
+	a := &hmacAuth{c: &conf.HMACAuth{Secret: "secret", MaxAge: xtime.Duration(time.Hour)}}
+	sign := func(payload string) []byte {
+		p := base64.RawURLEncoding.EncodeToString([]byte(payload))
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(p))
+		return []byte(p + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)))
+	}
+	now := time.Now().Unix()
+	res, err := a.Auth(context.Background(), "", sign(`{"mid":7,"platform":"ios","ts":`+strconv.FormatInt(now, 10)+`}`))
+	if err != nil || res.Mid != 7 || res.Platform != "ios" {
+		t.Fatalf("token:%+v error(%v)", res, err)
+	}
+	if _, err = a.Auth(context.Background(), "", sign(`{"mid":7,"ts":1}`)); err != ErrTokenExpired {
+		t.Fatalf("expired error(%v)", err)
+	}
+	token := sign(`{"mid":7}`)
+	token[len(token)-1] ^= 1
+	if _, err = a.Auth(context.Background(), "", token); err != ErrTokenInvalid {
+		t.Fatalf("tampered error(%v)", err)
+	}
+}
+
+func TestNewAuthenticatorInsecure(t *testing.T) {
+	if _, err := NewAuthenticator(&conf.Auth{Type: "json"}, nil); err == nil {
+		t.Fatal("json auth without insecure want error")
+	}
+	if _, err := NewAuthenticator(&conf.Auth{Type: "json", Insecure: true}, nil); err != nil {
+		t.Fatalf("json auth with insecure error(%v)", err)
+	}
+	// 默认配置是没有密钥的jwt,同样拒绝启动
+	if _, err := NewAuthenticator(conf.Default().Auth, nil); err == nil {
+		t.Fatal("default auth without secret want error")
+	}
+}
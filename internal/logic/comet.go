@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/comet"
+	discovery "github.com/gyy0727/mygoim/pkg/discovery"
+	"google.golang.org/grpc"
+)
+
+const _cometAppID = "goim.comet" //*comet在服务发现中的名称
+
+// *直接调用comet的rpc客户端,按需建立连接,用于需要汇总所有comet结果的查询
+type cometClients struct {
+	mutex   sync.Mutex
+	clients map[string]comet.CometClient //*key为comet的地址
+	conns   map[string]*grpc.ClientConn  //*key为comet的地址
+}
+
+// *返回服务发现中所有comet的rpc客户端,key为comet的serverID,已下线的comet会关闭连接
+func (l *Logic) comets() map[string]comet.CometClient {
+	nodes := l.dis.GetServiceNodes(_cometAppID)
+	res := make(map[string]comet.CometClient, len(nodes))
+	addrs := make(map[string]struct{}, len(nodes))
+	l.cometClients.mutex.Lock()
+	defer l.cometClients.mutex.Unlock()
+	if l.cometClients.clients == nil {
+		l.cometClients.clients = make(map[string]comet.CometClient)
+		l.cometClients.conns = make(map[string]*grpc.ClientConn)
+	}
+	for _, nd := range nodes {
+		addrs[nd.Addr] = struct{}{}
+		client, ok := l.cometClients.clients[nd.Addr]
+		if !ok {
+			conn, err := grpc.Dial(nd.Addr, grpc.WithInsecure())
+			if err != nil {
+				log.Errorf("grpc.Dial(%s) error(%v)", nd.Addr, err)
+				continue
+			}
+			client = comet.NewCometClient(conn)
+			l.cometClients.clients[nd.Addr] = client
+			l.cometClients.conns[nd.Addr] = conn
+		}
+		res[cometServerID(nd)] = client
+	}
+	for addr, conn := range l.cometClients.conns {
+		if _, ok := addrs[addr]; !ok {
+			conn.Close()
+			delete(l.cometClients.conns, addr)
+			delete(l.cometClients.clients, addr)
+		}
+	}
+	return res
+}
+
+// *并发调用所有comet,fn返回错误的comet会被跳过
+func (l *Logic) eachComet(c context.Context, fn func(ctx context.Context, server string, client comet.CometClient) error) {
+	var wg sync.WaitGroup
+	for server, client := range l.comets() {
+		wg.Add(1)
+		go func(server string, client comet.CometClient) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c, time.Duration(l.c.RPCClient.Timeout))
+			defer cancel()
+			if err := fn(ctx, server, client); err != nil {
+				log.Errorf("call comet server:%s error(%v)", server, err)
+			}
+		}(server, client)
+	}
+	wg.Wait()
+}
+
+// *comet以主机名作为serverID写入映射关系,没有主机名时退化为地址
+func cometServerID(nd *discovery.Node) string {
+	if host := nd.Metadata[discovery.MetaHostname]; host != "" {
+		return host
+	}
+	return nd.Addr
+}
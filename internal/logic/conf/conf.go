@@ -2,6 +2,7 @@ package conf
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -36,6 +37,34 @@ func Init() (err error) {
 	return
 }
 
+// *重新解析配置文件并校验,用于收到SIGHUP时热加载
+func Load() (c *Config, err error) {
+	c = Default()
+	if _, err = toml.DecodeFile(confPath, &c); err != nil {
+		return
+	}
+	err = c.Validate()
+	return
+}
+
+// *校验可以热加载的配置,认证配置在新建认证器时校验
+func (c *Config) Validate() error {
+	b := c.Backoff
+	if b == nil || b.BaseDelay <= 0 || b.MaxDelay < b.BaseDelay || b.Factor < 1 || b.Jitter < 0 {
+		return fmt.Errorf("invalid backoff:%+v", b)
+	}
+	provinces := make(map[string]string)
+	for region, ps := range c.Regions {
+		for _, p := range ps {
+			if old, ok := provinces[p]; ok && old != region {
+				return fmt.Errorf("province %s in both region %s and %s", p, old, region)
+			}
+			provinces[p] = region
+		}
+	}
+	return nil
+}
+
 // *Default 函数返回一个默认的 Config 对象
 func Default() *Config {
 	return &Config{
@@ -64,6 +93,47 @@ func Default() *Config {
 			KeepAliveTimeout:  xtime.Duration(time.Second * 20),
 		},
 		Backoff: &Backoff{MaxDelay: 300, BaseDelay: 3, Factor: 1.8, Jitter: 1.3},
+		Offline: &Offline{
+			Open:   false,
+			Store:  "redis",
+			Max:    100,
+			Expire: xtime.Duration(time.Hour * 24 * 7),
+		},
+		Seq: &Seq{
+			Open:   false,
+			Max:    200,
+			Expire: xtime.Duration(time.Hour * 24 * 7),
+			Sync:   50,
+		},
+		History: &History{
+			Open:   false,
+			Size:   50,
+			Expire: xtime.Duration(time.Hour * 24),
+		},
+		Upstream: &Upstream{},
+		Presence: &Presence{Routines: 32, Buffer: 1024},
+		Device:   &Device{},
+		Auth: &Auth{
+			Type: "jwt",
+			JWT: &JWTAuth{
+				Alg:           "HS256",
+				MidClaim:      "mid",
+				RoomClaim:     "room_id",
+				PlatformClaim: "platform",
+				AcceptsClaim:  "accepts",
+			},
+			HMAC:   &HMACAuth{},
+			Cookie: &CookieAuth{Name: "goim_session"},
+		},
+		Room: &Room{Rule: "open"},
+		Rebalance: &Rebalance{
+			Open:      false,
+			Interval:  xtime.Duration(time.Minute),
+			Threshold: 0.2,
+			Fraction:  0.5,
+			MaxShed:   1000,
+			MinNodes:  2,
+		},
 	}
 }
 
@@ -76,8 +146,17 @@ type Config struct {
 	Kafka      *Kafka      //*Kafka 相关的配置
 	Redis      *Redis      //*Redis 相关的配置
 	// Node       *Node               //*节点相关的配置
-	Backoff *Backoff            //*重试策略相关的配置
-	Regions map[string][]string //*区域映射配
+	Backoff   *Backoff            //*重试策略相关的配置
+	Regions   map[string][]string //*区域映射配
+	Offline   *Offline            //*离线消息相关的配置
+	Seq       *Seq                //*消息序列号相关的配置
+	History   *History            //*房间历史消息相关的配置
+	Upstream  *Upstream           //*客户端上行消息相关的配置
+	Presence  *Presence           //*上下线通知相关的配置
+	Device    *Device             //*多端登录相关的配置
+	Auth      *Auth               //*连接认证相关的配置
+	Room      *Room               //*加入房间鉴权相关的配置
+	Rebalance *Rebalance          //*comet之间连接再均衡的配置
 }
 
 type EtcdConfig struct {
@@ -148,3 +227,202 @@ type HTTPServer struct {
 	ReadTimeout  xtime.Duration //*读超时
 	WriteTimeout xtime.Duration //*写超时
 }
+
+// *离线消息配置
+type Offline struct {
+	Open   bool           //*是否开启离线消息
+	Store  string         //*存储方式: redis(默认)/memory
+	Max    int            //*每个用户每种op默认保留的消息条数
+	Expire xtime.Duration //*默认的保留时间
+	Ops    []*OfflineOp   //*按op单独配置的保留策略
+}
+
+// *单个op的离线消息保留策略,Max<=0表示该op不保存离线消息
+type OfflineOp struct {
+	Op     int32          //*操作码
+	Max    int            //*保留的消息条数
+	Expire xtime.Duration //*保留时间
+}
+
+// *消息序列号配置
+type Seq struct {
+	Open   bool           //*是否为单推消息分配序列号
+	Max    int            //*每个用户保留的可同步消息条数
+	Expire xtime.Duration //*可同步消息的保留时间
+	Sync   int            //*单次同步最多返回的消息条数
+}
+
+// *客户端上行消息配置,Topic为空且没有匹配的Ops时不投递
+type Upstream struct {
+	Topic string        //*上行消息默认投递的Kafka主题
+	Ops   []*UpstreamOp //*按op单独配置的投递主题
+}
+
+// *单个op的上行消息投递主题
+type UpstreamOp struct {
+	Op    int32  //*操作码
+	Topic string //*投递的Kafka主题
+}
+
+// *返回op对应的投递主题
+func (u *Upstream) Route(op int32) string {
+	if u == nil {
+		return ""
+	}
+	for _, o := range u.Ops {
+		if o.Op == op {
+			return o.Topic
+		}
+	}
+	return u.Topic
+}
+
+// *上下线通知配置
+type Presence struct {
+	Notify   bool   //*是否向关注者推送上下线通知
+	Topic    string //*上下线事件投递的Kafka主题,为空时不投递
+	Routines int    //*处理上下线事件的协程数,同一用户的事件由同一个协程按顺序处理
+	Buffer   int    //*每个协程的事件队列长度
+}
+
+// *连接认证配置
+type Auth struct {
+	Type     string      //*认证方式: jwt(默认)/hmac/cookie/json(不校验,只用于开发环境)
+	Insecure bool        //*是否允许使用不校验的json认证
+	JWT      *JWTAuth    //*jwt认证的配置
+	HMAC     *HMACAuth   //*hmac签名认证的配置
+	Cookie   *CookieAuth //*cookie会话认证的配置
+}
+
+// *jwt认证配置
+type JWTAuth struct {
+	Alg           string         //*签名算法: HS256/RS256
+	Secret        string         //*HS256的密钥
+	PublicKey     string         //*RS256的公钥文件路径,PEM格式
+	Issuer        string         //*签发者,为空时不校验
+	Leeway        xtime.Duration //*校验过期时间时允许的时钟误差
+	MidClaim      string         //*用户ID对应的claim
+	RoomClaim     string         //*房间ID对应的claim
+	PlatformClaim string         //*平台对应的claim
+	AcceptsClaim  string         //*订阅的操作码对应的claim
+}
+
+// *hmac签名认证配置,token格式为 base64url(json).base64url(hmac-sha256)
+type HMACAuth struct {
+	Secret string         //*签名密钥
+	MaxAge xtime.Duration //*token的有效期,为0时不校验签发时间
+}
+
+// *cookie会话认证配置,会话保存在redis中
+type CookieAuth struct {
+	Name string //*保存会话ID的cookie名称
+}
+
+// *加入房间的鉴权规则: open(不校验)/allow(redis中的允许名单)/password(redis中的房间密码)/ticket(hmac签名的票据)
+type Room struct {
+	Rule   string      //*默认规则
+	Secret string      //*默认的票据签名密钥
+	Types  []*RoomType //*按房间类型单独配置的规则
+}
+
+// *单个房间类型的鉴权规则
+type RoomType struct {
+	Type   string //*房间类型
+	Rule   string //*鉴权规则
+	Secret string //*票据签名密钥,为空时使用默认密钥
+}
+
+// *返回房间类型对应的鉴权规则和票据签名密钥
+func (r *Room) Policy(typ string) (rule, secret string) {
+	for _, t := range r.Types {
+		if t.Type == typ {
+			if secret = t.Secret; secret == "" {
+				secret = r.Secret
+			}
+			return t.Rule, secret
+		}
+	}
+	return r.Rule, r.Secret
+}
+
+// *多端登录策略,超出限制时踢掉最早登录的连接
+type Device struct {
+	Open   bool           //*是否开启多端登录限制
+	Max    int            //*每个用户最多同时在线的连接数,<=0表示不限制
+	Groups []*DeviceGroup //*按平台分组的限制,例如一个移动端加一个网页端
+}
+
+// *一组平台共享的在线连接数限制
+type DeviceGroup struct {
+	Name      string   //*分组名称
+	Platforms []string //*属于该分组的平台
+	Max       int      //*该分组最多同时在线的连接数,<=0表示不限制
+}
+
+// *返回平台所属的分组,不属于任何分组时返回nil
+func (d *Device) Group(platform string) *DeviceGroup {
+	for _, g := range d.Groups {
+		for _, p := range g.Platforms {
+			if p == platform {
+				return g
+			}
+		}
+	}
+	return nil
+}
+
+// *房间历史消息配置
+type History struct {
+	Open   bool           //*是否保存房间历史消息
+	Size   int            //*每个房间默认保留的消息条数
+	Expire xtime.Duration //*默认的保留时间
+	Types  []*HistoryType //*按房间类型单独配置的保留策略
+}
+
+// *单个房间类型的历史消息保留策略,Size<=0表示该类型不保存历史消息
+type HistoryType struct {
+	Type   string         //*房间类型
+	Size   int            //*保留的消息条数
+	Expire xtime.Duration //*保留时间
+}
+
+// *返回房间类型对应的保留条数和保留时间
+func (h *History) Policy(typ string) (size int, expire time.Duration) {
+	for _, t := range h.Types {
+		if t.Type == typ {
+			return t.Size, time.Duration(t.Expire)
+		}
+	}
+	return h.Size, time.Duration(h.Expire)
+}
+
+// *返回op对应的保留条数和保留时间
+func (o *Offline) Policy(op int32) (max int, expire time.Duration) {
+	for _, p := range o.Ops {
+		if p.Op == op {
+			return p.Max, time.Duration(p.Expire)
+		}
+	}
+	return o.Max, time.Duration(o.Expire)
+}
+
+// *返回所有策略中最长的保留时间
+func (o *Offline) MaxExpire() time.Duration {
+	expire := time.Duration(o.Expire)
+	for _, p := range o.Ops {
+		if time.Duration(p.Expire) > expire {
+			expire = time.Duration(p.Expire)
+		}
+	}
+	return expire
+}
+
+// *comet之间连接再均衡的配置,连接数来自服务发现中comet上报的元数据
+type Rebalance struct {
+	Open      bool           //*是否开启连接再均衡
+	Interval  xtime.Duration //*两次检查的间隔,需要大于comet上报连接数的周期
+	Threshold float64        //*连接数超过按权重分配的目标值的比例,超过时视为过载
+	Fraction  float64        //*每次转移超出目标值部分的比例
+	MaxShed   int            //*每次所有comet合计最多转移的连接数
+	MinNodes  int            //*在线的comet少于该数量时不做均衡
+}
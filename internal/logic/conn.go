@@ -2,26 +2,20 @@ package logic
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 	log "github.com/golang/glog"
 	"github.com/google/uuid"
+	pb "github.com/gyy0727/mygoim/api/logic"
 	"github.com/gyy0727/mygoim/api/protocol"
 	"github.com/gyy0727/mygoim/internal/logic/model"
 )
 
 
 //*主要的逻辑就是将用户和server的映射关系存储在redis中，
-func (l *Logic) Connect(c context.Context, server, cookie string, token []byte) (mid int64, key, roomID string, accepts []int32, hb int64, err error) {
-	var params struct {
-		Mid      int64   `json:"mid"`
-		Key      string  `json:"key"`
-		RoomID   string  `json:"room_id"`
-		Platform string  `json:"platform"`
-		Accepts  []int32 `json:"accepts"`
-	}
-	if err = json.Unmarshal(token, &params); err != nil {
-		log.Errorf("json.Unmarshal(%s) error(%v)", token, err)
+func (l *Logic) Connect(c context.Context, server, cookie, ip string, token []byte) (mid int64, key, roomID string, accepts []int32, hb int64, err error) {
+	params, err := l.authenticator().Auth(c, cookie, token)
+	if err != nil {
+		log.Errorf("l.auth.Auth() server:%s ip:%s error(%v)", server, ip, err)
 		return
 	}
 	mid = params.Mid
@@ -31,19 +25,66 @@ func (l *Logic) Connect(c context.Context, server, cookie string, token []byte)
 	if key = params.Key; key == "" {
 		key = uuid.New().String()
 	}
-	if err = l.dao.AddMapping(c, mid, key, server); err != nil {
+	//*点对点消息和上下线通知按mid推送给用户的每个连接,comet按accepts过滤,不能依赖客户端声明
+	if mid > 0 {
+		accepts = ensureAccepts(accepts, protocol.OpSendMsg)
+		if l.c.Presence.Notify {
+			accepts = ensureAccepts(accepts, protocol.OpPresenceChange)
+		}
+	}
+	//*token中的房间同样需要鉴权,不通过时只建立连接不加入房间
+	if code, err := l.AuthorizeRoom(c, mid, key, server, roomID, ""); err != nil || code != protocol.RoomAuthOK {
+		roomID = ""
+	}
+	var online bool
+	if online, err = l.dao.AddMapping(c, mid, key, server); err != nil {
 		log.Errorf("l.dao.AddMapping(%d,%s,%s) error(%v)", mid, key, server, err)
+	} else if mid > 0 {
+		info := &model.KeyInfo{Key: key, Server: server, Platform: params.Platform, IP: ip, Connected: time.Now().Unix()}
+		if err := l.dao.AddKeyInfo(c, info); err != nil {
+			log.Errorf("l.dao.AddKeyInfo(%d,%s) error(%v)", mid, key, err)
+		}
+		if l.c.Device.Open && !online {
+			l.enforceDevice(c, info, mid)
+		}
+		if l.offline != nil {
+			go l.replayOffline(context.Background(), mid, key, server)
+		}
+		if online {
+			l.presenceChanged(mid, key, server, true)
+		}
 	}
-	log.Infof("conn connected key:%s server:%s mid:%d token:%s", key, server, mid, token)
+	log.Infof("conn connected key:%s server:%s mid:%d", key, server, mid)
 	return
 }
 
+// *把服务端主动推送给用户的操作码加入accepts,已包含的不重复添加
+func ensureAccepts(accepts []int32, ops ...int32) []int32 {
+	for _, op := range ops {
+		found := false
+		for _, a := range accepts {
+			if a == op {
+				found = true
+				break
+			}
+		}
+		if !found {
+			accepts = append(accepts, op)
+		}
+	}
+	return accepts
+}
+
 //*将用户和server的映射关系从redis中删除
 func (l *Logic) Disconnect(c context.Context, mid int64, key, server string) (has bool, err error) {
-	if has, err = l.dao.DelMapping(c, mid, key, server); err != nil {
+	var offline bool
+	if has, offline, err = l.dao.DelMapping(c, mid, key, server); err != nil {
 		log.Errorf("l.dao.DelMapping(%d,%s) error(%v)", mid, key, server)
 		return
 	}
+	if offline {
+		l.presenceChanged(mid, key, server, false)
+	}
 	log.Infof("conn disconnected key:%s server:%s mid:%d", key, server, mid)
 	return
 }
@@ -56,10 +97,15 @@ func (l *Logic) Heartbeat(c context.Context, mid int64, key, server string) (err
 		return
 	}
 	if !has {
-		if err = l.dao.AddMapping(c, mid, key, server); err != nil {
+		var online bool
+		if online, err = l.dao.AddMapping(c, mid, key, server); err != nil {
 			log.Errorf("l.dao.AddMapping(%d,%s,%s) error(%v)", mid, key, server, err)
 			return
 		}
+		//*映射过期后重新添加,同样视为上线
+		if online {
+			l.presenceChanged(mid, key, server, true)
+		}
 	}
 	log.Infof("conn heartbeat key:%s server:%s mid:%d", key, server, mid)
 	return
@@ -79,7 +125,23 @@ func (l *Logic) RenewOnline(c context.Context, server string, roomCount map[stri
 }
 
 //*接受一个消息 
-func (l *Logic) Receive(c context.Context, mid int64, proto *protocol.Proto) (err error) {
+func (l *Logic) Receive(c context.Context, mid int64, key, server string, proto *protocol.Proto) (err error) {
 	log.Infof("receive mid:%d message:%+v", mid, proto)
+	topic := l.c.Upstream.Route(proto.Op)
+	if topic == "" {
+		return
+	}
+	msg := &pb.UpstreamMsg{
+		Mid:    mid,
+		Key:    key,
+		Server: server,
+		Op:     proto.Op,
+		Seq:    proto.Seq,
+		Body:   proto.Body,
+		Ts:     time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	if err = l.dao.PushUpstream(c, topic, msg); err != nil {
+		log.Errorf("l.dao.PushUpstream(%s,%d,%d) error(%v)", topic, mid, proto.Op, err)
+	}
 	return
 }
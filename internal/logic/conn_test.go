@@ -0,0 +1,27 @@
+package logic
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gyy0727/mygoim/api/protocol"
+)
+
+func TestEnsureAccepts(t *testing.T) {
+	tests := []struct {
+		name    string
+		accepts []int32
+		want    []int32
+	}{
+		{"empty", nil, []int32{protocol.OpSendMsg}},
+		{"missing", []int32{1000, 1001}, []int32{1000, 1001, protocol.OpSendMsg}},
+		{"present", []int32{protocol.OpSendMsg, 1000}, []int32{protocol.OpSendMsg, 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ensureAccepts(tt.accepts, protocol.OpSendMsg); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ensureAccepts(%v) = %v; want %v", tt.accepts, got, tt.want)
+			}
+		})
+	}
+}
@@ -56,7 +56,7 @@ func newRedis(c *conf.Redis) *redis.Pool {
 			if err != nil {
 				return nil, err
 			}
-			return conn, nil
+			return &metricConn{Conn: conn}, nil
 		},
 	}
 }
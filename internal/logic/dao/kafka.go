@@ -11,13 +11,14 @@ import (
 )
 
 //*将消息推送到Kafka消息队列的方法
-func (d *Dao) PushMsg(c context.Context, op int32, server string, keys []string, msg []byte) (err error) {
+func (d *Dao) PushMsg(c context.Context, op, seq int32, server string, keys []string, msg []byte) (err error) {
 	pushMsg := &pb.PushMsg{
 		Type:      pb.PushMsg_PUSH,
 		Operation: op,
 		Server:    server,
 		Keys:      keys,
 		Msg:       msg,
+		Seq:       seq,
 	}
 	b, err := proto.Marshal(pushMsg)
 	if err != nil {
@@ -28,7 +29,7 @@ func (d *Dao) PushMsg(c context.Context, op int32, server string, keys []string,
 		Topic: d.c.Kafka.Topic,
 		Value: sarama.ByteEncoder(b),
 	}
-	if _, _, err = d.kafkaPub.SendMessage(m); err != nil {
+	if err = d.sendKafka(m); err != nil {
 		log.Errorf("PushMsg.send(push pushMsg:%v) error(%v)", pushMsg, err)
 	}
 	return
@@ -51,12 +52,35 @@ func (d *Dao) BroadcastRoomMsg(c context.Context, op int32, room string, msg []b
 		Topic: d.c.Kafka.Topic,
 		Value: sarama.ByteEncoder(b),
 	}
-	if _, _, err = d.kafkaPub.SendMessage(m); err != nil {
+	if err = d.sendKafka(m); err != nil {
 		log.Errorf("PushMsg.send(broadcast_room pushMsg:%v) error(%v)", pushMsg, err)
 	}
 	return
 }
 
+//*通过job踢掉指定comet上的连接
+func (d *Dao) KickKeys(c context.Context, server string, keys []string, reason int32) (err error) {
+	pushMsg := &pb.PushMsg{
+		Type:   pb.PushMsg_KICK,
+		Server: server,
+		Keys:   keys,
+		Reason: reason,
+	}
+	b, err := proto.Marshal(pushMsg)
+	if err != nil {
+		return
+	}
+	m := &sarama.ProducerMessage{
+		Key:   sarama.StringEncoder(keys[0]),
+		Topic: d.c.Kafka.Topic,
+		Value: sarama.ByteEncoder(b),
+	}
+	if err = d.sendKafka(m); err != nil {
+		log.Errorf("PushMsg.send(kick pushMsg:%v) error(%v)", pushMsg, err)
+	}
+	return
+}
+
 //*用于将消息广播到所有客户端
 func (d *Dao) BroadcastMsg(c context.Context, op, speed int32, msg []byte) (err error) {
 	pushMsg := &pb.PushMsg{
@@ -74,8 +98,42 @@ func (d *Dao) BroadcastMsg(c context.Context, op, speed int32, msg []byte) (err
 		Topic: d.c.Kafka.Topic,
 		Value: sarama.ByteEncoder(b),
 	}
-	if _, _, err = d.kafkaPub.SendMessage(m); err != nil {
+	if err = d.sendKafka(m); err != nil {
 		log.Errorf("PushMsg.send(broadcast pushMsg:%v) error(%v)", pushMsg, err)
 	}
 	return
 }
+
+//*将客户端上行消息投递到指定的Kafka主题
+func (d *Dao) PushUpstream(c context.Context, topic string, msg *pb.UpstreamMsg) (err error) {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return
+	}
+	m := &sarama.ProducerMessage{
+		Key:   sarama.StringEncoder(strconv.FormatInt(msg.Mid, 10)),
+		Topic: topic,
+		Value: sarama.ByteEncoder(b),
+	}
+	if err = d.sendKafka(m); err != nil {
+		log.Errorf("PushMsg.send(upstream msg:%v) error(%v)", msg, err)
+	}
+	return
+}
+
+// *把用户上下线事件投递到Kafka
+func (d *Dao) PushPresence(c context.Context, topic string, event *pb.PresenceEvent) (err error) {
+	b, err := proto.Marshal(event)
+	if err != nil {
+		return
+	}
+	m := &sarama.ProducerMessage{
+		Key:   sarama.StringEncoder(strconv.FormatInt(event.Mid, 10)),
+		Topic: topic,
+		Value: sarama.ByteEncoder(b),
+	}
+	if err = d.sendKafka(m); err != nil {
+		log.Errorf("PushMsg.send(presence event:%v) error(%v)", event, err)
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	sarama "gopkg.in/Shopify/sarama.v1"
+)
+
+const (
+	metricNamespace = "goim"
+	metricSubsystem = "logic"
+)
+
+var (
+	// *redis命令的耗时,管道中的命令从管道开始计时到收到回复为止
+	metricRedisDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "redis_duration_seconds",
+		Help:      "Redis command latency by command.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	}, []string{"cmd"})
+	// *redis命令失败的次数
+	metricRedisErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "redis_errors_total",
+		Help:      "Failed redis commands by command.",
+	}, []string{"cmd"})
+	// *kafka发送消息的耗时
+	metricKafkaDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "kafka_produce_duration_seconds",
+		Help:      "Kafka produce latency by topic.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"topic"})
+	// *kafka发送消息失败的次数
+	metricKafkaErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "kafka_produce_errors_total",
+		Help:      "Failed kafka produces by topic.",
+	}, []string{"topic"})
+)
+
+func init() {
+	prometheus.MustRegister(metricRedisDuration, metricRedisErrors, metricKafkaDuration, metricKafkaErrors)
+}
+
+// *记录redis命令的耗时和错误
+func observeRedis(cmd string, start time.Time, err error) {
+	cmd = strings.ToUpper(cmd)
+	metricRedisDuration.WithLabelValues(cmd).Observe(time.Since(start).Seconds())
+	if err != nil && err != redis.ErrNil {
+		metricRedisErrors.WithLabelValues(cmd).Inc()
+	}
+}
+
+// *统计命令耗时的redis连接
+type metricConn struct {
+	redis.Conn
+	pending []string  //*已Send还未Receive的命令
+	start   time.Time //*当前管道第一条命令Send的时间
+}
+
+func (c *metricConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	start := time.Now()
+	reply, err := c.Conn.Do(cmd, args...)
+	//*Do会把管道中还没接收的回复一并读掉
+	for _, p := range c.pending {
+		observeRedis(p, c.start, err)
+	}
+	c.pending = c.pending[:0]
+	if cmd != "" {
+		observeRedis(cmd, start, err)
+	}
+	return reply, err
+}
+
+func (c *metricConn) Send(cmd string, args ...interface{}) error {
+	if len(c.pending) == 0 {
+		c.start = time.Now()
+	}
+	c.pending = append(c.pending, cmd)
+	return c.Conn.Send(cmd, args...)
+}
+
+func (c *metricConn) Receive() (interface{}, error) {
+	reply, err := c.Conn.Receive()
+	if len(c.pending) > 0 {
+		observeRedis(c.pending[0], c.start, err)
+		c.pending = c.pending[1:]
+	}
+	return reply, err
+}
+
+// *发送kafka消息并记录耗时和错误
+func (d *Dao) sendKafka(m *sarama.ProducerMessage) (err error) {
+	start := time.Now()
+	_, _, err = d.kafkaPub.SendMessage(m)
+	metricKafkaDuration.WithLabelValues(m.Topic).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metricKafkaErrors.WithLabelValues(m.Topic).Inc()
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package dao
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *离线消息存储,每个用户按op分别保留最近的max条消息
+type OfflineStore interface {
+	//*保存一条离线消息,超过max条时丢弃最旧的
+	AddOffline(c context.Context, mid int64, msg *model.OfflineMsg, max int, expire time.Duration) error
+	//*取出并删除用户所有的离线消息,按写入时间排序
+	PopOffline(c context.Context, mid int64) ([]*model.OfflineMsg, error)
+}
+
+// *根据配置选择离线消息的存储方式,默认使用redis
+func NewOfflineStore(c *conf.Offline, d *Dao) OfflineStore {
+	switch c.Store {
+	case "memory":
+		return NewMemoryOffline()
+	default:
+		return d
+	}
+}
+
+// *按写入时间排序离线消息
+func sortOffline(msgs []*model.OfflineMsg) {
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].Ts < msgs[j].Ts
+	})
+}
+
+// *基于内存的离线消息存储,用于测试和单机部署
+type MemoryOffline struct {
+	lock sync.Mutex
+	msgs map[int64]map[int32][]*memoryOfflineMsg //*mid -> op -> 消息列表
+}
+
+type memoryOfflineMsg struct {
+	msg    *model.OfflineMsg
+	expire time.Time
+}
+
+// *新建一个内存离线消息存储
+func NewMemoryOffline() *MemoryOffline {
+	return &MemoryOffline{msgs: make(map[int64]map[int32][]*memoryOfflineMsg)}
+}
+
+func (m *MemoryOffline) AddOffline(c context.Context, mid int64, msg *model.OfflineMsg, max int, expire time.Duration) error {
+	if max <= 0 {
+		return nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ops := m.msgs[mid]
+	if ops == nil {
+		ops = make(map[int32][]*memoryOfflineMsg)
+		m.msgs[mid] = ops
+	}
+	list := append(ops[msg.Op], &memoryOfflineMsg{msg: msg, expire: time.Now().Add(expire)})
+	if len(list) > max {
+		list = list[len(list)-max:]
+	}
+	ops[msg.Op] = list
+	return nil
+}
+
+func (m *MemoryOffline) PopOffline(c context.Context, mid int64) (msgs []*model.OfflineMsg, err error) {
+	m.lock.Lock()
+	ops := m.msgs[mid]
+	delete(m.msgs, mid)
+	m.lock.Unlock()
+	now := time.Now()
+	for _, list := range ops {
+		for _, om := range list {
+			if now.Before(om.expire) {
+				msgs = append(msgs, om.msg)
+			}
+		}
+	}
+	sortOffline(msgs)
+	return
+}
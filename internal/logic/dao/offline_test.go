@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// 测试内存离线消息存储
+func TestMemoryOffline(t *testing.T) {
+	var (
+		c   = context.Background()
+		s   = NewMemoryOffline()
+		mid = int64(123)
+	)
+	for i := int64(1); i <= 5; i++ {
+		if err := s.AddOffline(c, mid, &model.OfflineMsg{Op: 1, Msg: []byte("a"), Ts: i}, 3, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddOffline(c, mid, &model.OfflineMsg{Op: 2, Msg: []byte("b"), Ts: 4}, 3, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddOffline(c, mid, &model.OfflineMsg{Op: 3, Msg: []byte("c"), Ts: 6}, 3, -time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := s.PopOffline(c, mid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// op 1 只保留最近 3 条, op 3 已过期
+	want := []int64{3, 4, 4, 5}
+	if len(msgs) != len(want) {
+		t.Fatalf("PopOffline got %d msgs; want %d", len(msgs), len(want))
+	}
+	for i, msg := range msgs {
+		if msg.Ts != want[i] {
+			t.Errorf("msgs[%d].Ts = %d; want %d", i, msg.Ts, want[i])
+		}
+	}
+	if msgs, _ = s.PopOffline(c, mid); len(msgs) != 0 {
+		t.Errorf("PopOffline after pop got %d msgs; want 0", len(msgs))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	log "github.com/golang/glog"
 	"github.com/gomodule/redigo/redis"
@@ -13,9 +14,22 @@ import (
 )
 
 const (
-	_prefixMidServer    = "mid_%d" //*存储用户 ID（mid）与键（key）和服务器的映射关系
-	_prefixKeyServer    = "key_%s" //*存储键（key）与服务器的映射关系
-	_prefixServerOnline = "ol_%s"  //*存储服务器的在线状态信息
+	_prefixMidServer    = "mid_%d"          //*存储用户 ID（mid）与键（key）和服务器的映射关系
+	_prefixKeyServer    = "key_%s"          //*存储键（key）与服务器的映射关系
+	_prefixServerOnline = "ol_%s"           //*存储服务器的在线状态信息
+	_prefixOfflineOps   = "offline_{%d}"    //*存储用户有离线消息的op集合,mid作为hash tag,和离线消息列表在同一个slot
+	_prefixOfflineMsg   = "offline_{%d}_%d" //*存储用户某个op的离线消息列表
+	_prefixKeyMid       = "keymid_%s"       //*存储键（key）与用户 ID（mid）的映射关系
+	_prefixMidSeq       = "seq_{%d}"        //*存储用户当前的消息序列号,mid作为hash tag,和同步消息在同一个slot
+	_prefixMidSeqMsg    = "seqmsg_{%d}"     //*存储用户可同步的消息,score为序列号
+	_prefixRoomHistory  = "history_%s"      //*存储房间最近的历史消息列表
+	_prefixKeyInfo      = "keyinfo_%s"      //*存储连接的元数据
+	_prefixWatch        = "watch_%d"        //*存储用户关注在线状态的用户集合
+	_prefixWatchers     = "watchers_%d"     //*存储关注该用户在线状态的用户集合
+	_prefixSession      = "session_%s"      //*存储cookie会话对应的用户信息
+	_prefixRoomAllow    = "roomallow_%s"    //*存储允许加入房间的用户集合
+	_prefixRoomPassword = "roompwd_%s"      //*存储房间的密码
+	_prefixLock         = "lock_%s"         //*存储多个logic之间的互斥锁,值为持有者
 )
 
 // *用于生成 Redis 的键名
@@ -33,6 +47,71 @@ func keyServerOnline(key string) string {
 	return fmt.Sprintf(_prefixServerOnline, key)
 }
 
+// *用于生成 Redis 的键名
+func keyOfflineOps(mid int64) string {
+	return fmt.Sprintf(_prefixOfflineOps, mid)
+}
+
+// *用于生成 Redis 的键名
+func keyOfflineMsg(mid int64, op int32) string {
+	return fmt.Sprintf(_prefixOfflineMsg, mid, op)
+}
+
+// *用于生成 Redis 的键名
+func keyKeyMid(key string) string {
+	return fmt.Sprintf(_prefixKeyMid, key)
+}
+
+// *用于生成 Redis 的键名
+func keyMidSeq(mid int64) string {
+	return fmt.Sprintf(_prefixMidSeq, mid)
+}
+
+// *用于生成 Redis 的键名
+func keyMidSeqMsg(mid int64) string {
+	return fmt.Sprintf(_prefixMidSeqMsg, mid)
+}
+
+// *用于生成 Redis 的键名
+func keyRoomHistory(room string) string {
+	return fmt.Sprintf(_prefixRoomHistory, room)
+}
+
+// *用于生成 Redis 的键名
+func keyKeyInfo(key string) string {
+	return fmt.Sprintf(_prefixKeyInfo, key)
+}
+
+// *生成关注集合的key
+func keyWatch(mid int64) string {
+	return fmt.Sprintf(_prefixWatch, mid)
+}
+
+// *生成被关注集合的key
+func keyWatchers(mid int64) string {
+	return fmt.Sprintf(_prefixWatchers, mid)
+}
+
+// *生成会话的key
+func keySession(sid string) string {
+	return fmt.Sprintf(_prefixSession, sid)
+}
+
+// *生成房间允许名单的key
+func keyRoomAllow(room string) string {
+	return fmt.Sprintf(_prefixRoomAllow, room)
+}
+
+// *生成房间密码的key
+func keyRoomPassword(room string) string {
+	return fmt.Sprintf(_prefixRoomPassword, room)
+}
+
+// *生成互斥锁的key
+func keyLock(name string) string {
+	return fmt.Sprintf(_prefixLock, name)
+}
+
 // *通过发送 PING 命令检查 Redis 连接是否正常
 func (d *Dao) pingRedis(c context.Context) (err error) {
 	conn := d.redis.Get()
@@ -41,11 +120,56 @@ func (d *Dao) pingRedis(c context.Context) (err error) {
 	return
 }
 
+var (
+	//*添加mid的key并返回是否新增以及添加后的key数量,用于判断用户是否刚上线
+	_addMidServerScript = redis.NewScript(1, `
+local added = redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return {added, redis.call('HLEN', KEYS[1])}`)
+	//*删除mid的key并返回是否删除以及删除后的key数量,用于判断用户是否刚下线
+	_delMidServerScript = redis.NewScript(1, `
+local removed = redis.call('HDEL', KEYS[1], ARGV[1])
+return {removed, redis.call('HLEN', KEYS[1])}`)
+	//*取出并删除KEYS[2:]中的离线消息并从op集合KEYS[1]中移除对应的op(ARGV),在一个脚本中完成,并发的PopOffline不会重复取到同一条消息
+	_popOfflineScript = redis.NewScript(-1, `
+local res = {}
+for i = 2, #KEYS do
+	for _, msg in ipairs(redis.call('LRANGE', KEYS[i], 0, -1)) do
+		res[#res+1] = msg
+	end
+	redis.call('DEL', KEYS[i])
+	redis.call('SREM', KEYS[1], ARGV[i-1])
+end
+return res`)
+	//*分配下一个序列号并把消息(ARGV[1],不带seq的JSON)写入同步列表,ARGV[2]为保留条数,ARGV[3]为过期秒数
+	_addSeqMsgScript = redis.NewScript(2, `
+local seq = redis.call('INCR', KEYS[1])
+local max = tonumber(ARGV[2])
+if max > 0 then
+	local msg = cjson.decode(ARGV[1])
+	msg.seq = seq
+	redis.call('ZADD', KEYS[2], seq, cjson.encode(msg))
+	redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -(max + 1))
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+return seq`)
+)
+
+// *根据脚本的返回值判断是否发生了上下线的转换
+func midTransition(reply interface{}, count int64) (ok bool, err error) {
+	vals, err := redis.Int64s(reply, nil)
+	if err != nil || len(vals) != 2 {
+		return
+	}
+	return vals[0] == 1 && vals[1] == count, nil
+}
+
 // *添加用户与服务器的映射关系
 // *mid:用户 ID
 // *key:用户的唯一标识符
 // *server:服务器消息
-func (d *Dao) AddMapping(c context.Context, mid int64, key, server string) (err error) {
+// *online:是否为该用户的第一个连接
+func (d *Dao) AddMapping(c context.Context, mid int64, key, server string) (online bool, err error) {
 	//*获取连接
 	conn := d.redis.Get()
 	defer conn.Close()
@@ -54,12 +178,12 @@ func (d *Dao) AddMapping(c context.Context, mid int64, key, server string) (err
 	var n = 2
 	//*如果 mid 大于 0，添加 mid 到 server 的映射,并添加过期时间
 	if mid > 0 {
-		if err = conn.Send("HSET", keyMidServer(mid), key, server); err != nil {
+		if err = _addMidServerScript.Send(conn, keyMidServer(mid), key, server, d.redisExpire); err != nil {
 			log.Errorf("conn.Send(HSET %d,%s,%s) error(%v)", mid, server, key, err)
 			return
 		}
-		if err = conn.Send("EXPIRE", keyMidServer(mid), d.redisExpire); err != nil {
-			log.Errorf("conn.Send(EXPIRE %d,%s,%s) error(%v)", mid, key, server, err)
+		if err = conn.Send("SET", keyKeyMid(key), mid, "EX", d.redisExpire); err != nil {
+			log.Errorf("conn.Send(SET %s,%d) error(%v)", key, mid, err)
 			return
 		}
 		n += 2
@@ -78,10 +202,17 @@ func (d *Dao) AddMapping(c context.Context, mid int64, key, server string) (err
 	}
 	//* 接收 Redis 管道（Pipeline）中所有命令的执行结果，并检查是否有错误
 	for i := 0; i < n; i++ {
-		if _, err = conn.Receive(); err != nil {
+		var reply interface{}
+		if reply, err = conn.Receive(); err != nil {
 			log.Errorf("conn.Receive() error(%v)", err)
 			return
 		}
+		if i == 0 && mid > 0 {
+			if online, err = midTransition(reply, 1); err != nil {
+				log.Errorf("midTransition(%d,%s) error(%v)", mid, key, err)
+				return
+			}
+		}
 	}
 	return
 }
@@ -96,7 +227,15 @@ func (d *Dao) ExpireMapping(c context.Context, mid int64, key string) (has bool,
 			log.Errorf("conn.Send(EXPIRE %d,%s) error(%v)", mid, key, err)
 			return
 		}
-		n++
+		if err = conn.Send("EXPIRE", keyKeyMid(key), d.redisExpire); err != nil {
+			log.Errorf("conn.Send(EXPIRE %s) error(%v)", key, err)
+			return
+		}
+		if err = conn.Send("EXPIRE", keyKeyInfo(key), d.redisExpire); err != nil {
+			log.Errorf("conn.Send(EXPIRE %s) error(%v)", key, err)
+			return
+		}
+		n += 3
 	}
 	if err = conn.Send("EXPIRE", keyKeyServer(key), d.redisExpire); err != nil {
 		log.Errorf("conn.Send(EXPIRE %d,%s) error(%v)", mid, key, err)
@@ -116,7 +255,8 @@ func (d *Dao) ExpireMapping(c context.Context, mid int64, key string) (has bool,
 }
 
 // *该函数用于删除 Redis 中的某些键或哈希字段
-func (d *Dao) DelMapping(c context.Context, mid int64, key, server string) (has bool, err error) {
+// *offline:是否删除了该用户的最后一个连接
+func (d *Dao) DelMapping(c context.Context, mid int64, key, server string) (has, offline bool, err error) {
 	//*获取redis连接
 	conn := d.redis.Get()
 	defer conn.Close()
@@ -124,11 +264,19 @@ func (d *Dao) DelMapping(c context.Context, mid int64, key, server string) (has
 	n := 1
 	//*删除映射
 	if mid > 0 {
-		if err = conn.Send("HDEL", keyMidServer(mid), key); err != nil {
+		if err = _delMidServerScript.Send(conn, keyMidServer(mid), key); err != nil {
 			log.Errorf("conn.Send(HDEL %d,%s,%s) error(%v)", mid, key, server, err)
 			return
 		}
-		n++
+		if err = conn.Send("DEL", keyKeyMid(key)); err != nil {
+			log.Errorf("conn.Send(DEL %s) error(%v)", key, err)
+			return
+		}
+		if err = conn.Send("DEL", keyKeyInfo(key)); err != nil {
+			log.Errorf("conn.Send(DEL %s) error(%v)", key, err)
+			return
+		}
+		n += 3
 	}
 	//*删除映射
 	if err = conn.Send("DEL", keyKeyServer(key)); err != nil {
@@ -140,10 +288,22 @@ func (d *Dao) DelMapping(c context.Context, mid int64, key, server string) (has
 		return
 	}
 	for i := 0; i < n; i++ {
-		if has, err = redis.Bool(conn.Receive()); err != nil {
+		var reply interface{}
+		if reply, err = conn.Receive(); err != nil {
 			log.Errorf("conn.Receive() error(%v)", err)
 			return
 		}
+		if i == 0 && mid > 0 {
+			if offline, err = midTransition(reply, 0); err != nil {
+				log.Errorf("midTransition(%d,%s) error(%v)", mid, key, err)
+				return
+			}
+			continue
+		}
+		if has, err = redis.Bool(reply, nil); err != nil {
+			log.Errorf("redis.Bool() error(%v)", err)
+			return
+		}
 	}
 	return
 }
@@ -195,6 +355,359 @@ func (d *Dao) KeysByMids(c context.Context, mids []int64) (ress map[string]strin
 	return
 }
 
+// *从 Redis 中批量获取每个 mid 对应的 key 与服务器的映射
+func (d *Dao) MidKeyServers(c context.Context, mids []int64) (ress map[int64]map[string]string, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	ress = make(map[int64]map[string]string, len(mids))
+	for _, mid := range mids {
+		if err = conn.Send("HGETALL", keyMidServer(mid)); err != nil {
+			log.Errorf("conn.Do(HGETALL %d) error(%v)", mid, err)
+			return
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		log.Errorf("conn.Flush() error(%v)", err)
+		return
+	}
+	for _, mid := range mids {
+		var res map[string]string
+		if res, err = redis.StringMap(conn.Receive()); err != nil {
+			log.Errorf("conn.Receive() error(%v)", err)
+			return
+		}
+		if len(res) > 0 {
+			ress[mid] = res
+		}
+	}
+	return
+}
+
+// *从 Redis 中批量获取与指定 keys 对应的 mid,匿名连接为0
+func (d *Dao) MidsByKeys(c context.Context, keys []string) (mids []int64, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	var args []interface{}
+	for _, key := range keys {
+		args = append(args, keyKeyMid(key))
+	}
+	values, err := redis.Values(conn.Do("MGET", args...))
+	if err != nil {
+		log.Errorf("conn.Do(MGET %v) error(%v)", args, err)
+		return
+	}
+	mids = make([]int64, len(values))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		if mids[i], err = redis.Int64(v, nil); err != nil {
+			log.Errorf("redis.Int64(%v) error(%v)", v, err)
+			return
+		}
+	}
+	return
+}
+
+// *为每个用户分配下一个序列号,并把消息保存到用户的同步列表中,每个用户的INCR和ZADD在一个脚本中完成
+func (d *Dao) AddSeqMsgs(c context.Context, mids []int64, op int32, msg []byte, max int, expire time.Duration) (seqs map[int64]int32, err error) {
+	b, err := json.Marshal(&model.SeqMsg{Op: op, Msg: msg})
+	if err != nil {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	for _, mid := range mids {
+		if err = _addSeqMsgScript.Send(conn, keyMidSeq(mid), keyMidSeqMsg(mid), b, max, int64(expire/time.Second)); err != nil {
+			log.Errorf("_addSeqMsgScript.Send(%d) error(%v)", mid, err)
+			return
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		log.Errorf("conn.Flush() error(%v)", err)
+		return
+	}
+	seqs = make(map[int64]int32, len(mids))
+	for _, mid := range mids {
+		var seq int64
+		if seq, err = redis.Int64(conn.Receive()); err != nil {
+			log.Errorf("conn.Receive() error(%v)", err)
+			return
+		}
+		seqs[mid] = int32(seq)
+	}
+	return
+}
+
+// *获取用户序列号大于 seq 的消息,最多 limit 条
+func (d *Dao) SeqMsgs(c context.Context, mid int64, seq int32, limit int) (msgs []*model.SeqMsg, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	key := keyMidSeqMsg(mid)
+	bs, err := redis.ByteSlices(conn.Do("ZRANGEBYSCORE", key, fmt.Sprintf("(%d", seq), "+inf", "LIMIT", 0, limit))
+	if err != nil {
+		log.Errorf("conn.Do(ZRANGEBYSCORE %s,%d) error(%v)", key, seq, err)
+		return
+	}
+	for _, b := range bs {
+		msg := new(model.SeqMsg)
+		if err := json.Unmarshal(b, msg); err != nil {
+			log.Errorf("SeqMsgs json.Unmarshal(%s) error(%v)", b, err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return
+}
+
+// *追加一条房间历史消息,列表只保留最近的 size 条
+func (d *Dao) AddRoomHistory(c context.Context, room string, msg *model.HistoryMsg, size int, expire time.Duration) (err error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	key := keyRoomHistory(room)
+	if err = conn.Send("RPUSH", key, b); err != nil {
+		log.Errorf("conn.Send(RPUSH %s) error(%v)", key, err)
+		return
+	}
+	if err = conn.Send("LTRIM", key, -size, -1); err != nil {
+		log.Errorf("conn.Send(LTRIM %s,%d) error(%v)", key, size, err)
+		return
+	}
+	if err = conn.Send("EXPIRE", key, int64(expire/time.Second)); err != nil {
+		log.Errorf("conn.Send(EXPIRE %s) error(%v)", key, err)
+		return
+	}
+	if err = conn.Flush(); err != nil {
+		log.Errorf("conn.Flush() error(%v)", err)
+		return
+	}
+	for i := 0; i < 3; i++ {
+		if _, err = conn.Receive(); err != nil {
+			log.Errorf("conn.Receive() error(%v)", err)
+			return
+		}
+	}
+	return
+}
+
+// *获取房间最近的 limit 条历史消息,按时间先后排列
+func (d *Dao) RoomHistory(c context.Context, room string, limit int) (msgs []*model.HistoryMsg, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	key := keyRoomHistory(room)
+	bs, err := redis.ByteSlices(conn.Do("LRANGE", key, -limit, -1))
+	if err != nil {
+		log.Errorf("conn.Do(LRANGE %s,%d) error(%v)", key, limit, err)
+		return
+	}
+	for _, b := range bs {
+		msg := new(model.HistoryMsg)
+		if err := json.Unmarshal(b, msg); err != nil {
+			log.Errorf("RoomHistory json.Unmarshal(%s) error(%v)", b, err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return
+}
+
+// *记录连接的元数据
+func (d *Dao) AddKeyInfo(c context.Context, info *model.KeyInfo) (err error) {
+	b, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	if _, err = conn.Do("SET", keyKeyInfo(info.Key), b, "EX", d.redisExpire); err != nil {
+		log.Errorf("conn.Do(SET %s) error(%v)", info.Key, err)
+	}
+	return
+}
+
+// *批量获取连接的元数据,没有记录的 key 不在结果中
+func (d *Dao) KeyInfos(c context.Context, keys []string) (res map[string]*model.KeyInfo, err error) {
+	res = make(map[string]*model.KeyInfo, len(keys))
+	if len(keys) == 0 {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	var args []interface{}
+	for _, key := range keys {
+		args = append(args, keyKeyInfo(key))
+	}
+	bs, err := redis.ByteSlices(conn.Do("MGET", args...))
+	if err != nil {
+		log.Errorf("conn.Do(MGET %v) error(%v)", args, err)
+		return
+	}
+	for i, b := range bs {
+		if b == nil {
+			continue
+		}
+		info := new(model.KeyInfo)
+		if err := json.Unmarshal(b, info); err != nil {
+			log.Errorf("KeyInfos json.Unmarshal(%s) error(%v)", b, err)
+			continue
+		}
+		res[keys[i]] = info
+	}
+	return
+}
+
+// *添加在线状态的关注,同时维护反向的被关注集合
+func (d *Dao) AddWatch(c context.Context, mid int64, targets []int64) (err error) {
+	return d.updateWatch(c, "SADD", mid, targets)
+}
+
+// *取消在线状态的关注
+func (d *Dao) DelWatch(c context.Context, mid int64, targets []int64) (err error) {
+	return d.updateWatch(c, "SREM", mid, targets)
+}
+
+func (d *Dao) updateWatch(c context.Context, cmd string, mid int64, targets []int64) (err error) {
+	if len(targets) == 0 {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	args := []interface{}{keyWatch(mid)}
+	for _, target := range targets {
+		args = append(args, target)
+	}
+	if err = conn.Send(cmd, args...); err != nil {
+		log.Errorf("conn.Send(%s %d) error(%v)", cmd, mid, err)
+		return
+	}
+	for _, target := range targets {
+		if err = conn.Send(cmd, keyWatchers(target), mid); err != nil {
+			log.Errorf("conn.Send(%s %d,%d) error(%v)", cmd, target, mid, err)
+			return
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		log.Errorf("conn.Flush() error(%v)", err)
+		return
+	}
+	for i := 0; i < len(targets)+1; i++ {
+		if _, err = conn.Receive(); err != nil {
+			log.Errorf("conn.Receive() error(%v)", err)
+			return
+		}
+	}
+	return
+}
+
+// *获取用户关注的所有用户
+func (d *Dao) Watching(c context.Context, mid int64) (mids []int64, err error) {
+	return d.watchMembers(keyWatch(mid))
+}
+
+// *获取关注该用户在线状态的所有用户
+func (d *Dao) Watchers(c context.Context, mid int64) (mids []int64, err error) {
+	return d.watchMembers(keyWatchers(mid))
+}
+
+func (d *Dao) watchMembers(key string) (mids []int64, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	if mids, err = redis.Int64s(conn.Do("SMEMBERS", key)); err != nil {
+		log.Errorf("conn.Do(SMEMBERS %s) error(%v)", key, err)
+	}
+	return
+}
+
+// *获取cookie会话对应的用户信息,会话不存在时返回nil
+func (d *Dao) Session(c context.Context, sid string) (token *model.AuthToken, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	b, err := redis.Bytes(conn.Do("GET", keySession(sid)))
+	if err != nil {
+		if err == redis.ErrNil {
+			err = nil
+		} else {
+			log.Errorf("conn.Do(GET %s) error(%v)", sid, err)
+		}
+		return
+	}
+	token = new(model.AuthToken)
+	if err = json.Unmarshal(b, token); err != nil {
+		log.Errorf("Session json.Unmarshal(%s) error(%v)", b, err)
+		token = nil
+	}
+	return
+}
+
+// *把用户加入房间的允许名单
+func (d *Dao) AddRoomAllow(c context.Context, room string, mids []int64) (err error) {
+	return d.updateRoomAllow("SADD", room, mids)
+}
+
+// *把用户移出房间的允许名单
+func (d *Dao) DelRoomAllow(c context.Context, room string, mids []int64) (err error) {
+	return d.updateRoomAllow("SREM", room, mids)
+}
+
+func (d *Dao) updateRoomAllow(cmd, room string, mids []int64) (err error) {
+	if len(mids) == 0 {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	args := []interface{}{keyRoomAllow(room)}
+	for _, mid := range mids {
+		args = append(args, mid)
+	}
+	if _, err = conn.Do(cmd, args...); err != nil {
+		log.Errorf("conn.Do(%s %s) error(%v)", cmd, room, err)
+	}
+	return
+}
+
+// *判断用户是否在房间的允许名单中
+func (d *Dao) RoomAllowed(c context.Context, room string, mid int64) (ok bool, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	if ok, err = redis.Bool(conn.Do("SISMEMBER", keyRoomAllow(room), mid)); err != nil {
+		log.Errorf("conn.Do(SISMEMBER %s,%d) error(%v)", room, mid, err)
+	}
+	return
+}
+
+// *设置房间的密码,密码为空时删除
+func (d *Dao) SetRoomPassword(c context.Context, room, password string) (err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	if password == "" {
+		_, err = conn.Do("DEL", keyRoomPassword(room))
+	} else {
+		_, err = conn.Do("SET", keyRoomPassword(room), password)
+	}
+	if err != nil {
+		log.Errorf("conn.Do(SET %s) error(%v)", room, err)
+	}
+	return
+}
+
+// *获取房间的密码,没有设置时返回空
+func (d *Dao) RoomPassword(c context.Context, room string) (password string, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	if password, err = redis.String(conn.Do("GET", keyRoomPassword(room))); err != nil {
+		if err == redis.ErrNil {
+			err = nil
+		} else {
+			log.Errorf("conn.Do(GET %s) error(%v)", room, err)
+		}
+	}
+	return
+}
+
 //*将服务器的在线信息存储到 Redis 中
 func (d *Dao) AddServerOnline(c context.Context, server string, online *model.Online) (err error) {
 	roomsMap := map[uint32]map[string]int32{}
@@ -290,3 +803,103 @@ func (d *Dao) DelServerOnline(c context.Context, server string) (err error) {
 	}
 	return
 }
+
+// *保存一条离线消息,列表只保留最近的 max 条
+func (d *Dao) AddOffline(c context.Context, mid int64, msg *model.OfflineMsg, max int, expire time.Duration) (err error) {
+	if max <= 0 {
+		return
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	conn := d.redis.Get()
+	defer conn.Close()
+	key := keyOfflineMsg(mid, msg.Op)
+	if err = conn.Send("RPUSH", key, b); err != nil {
+		log.Errorf("conn.Send(RPUSH %s) error(%v)", key, err)
+		return
+	}
+	if err = conn.Send("LTRIM", key, -max, -1); err != nil {
+		log.Errorf("conn.Send(LTRIM %s,%d) error(%v)", key, max, err)
+		return
+	}
+	if err = conn.Send("EXPIRE", key, int64(expire/time.Second)); err != nil {
+		log.Errorf("conn.Send(EXPIRE %s) error(%v)", key, err)
+		return
+	}
+	if err = conn.Send("SADD", keyOfflineOps(mid), msg.Op); err != nil {
+		log.Errorf("conn.Send(SADD %d,%d) error(%v)", mid, msg.Op, err)
+		return
+	}
+	if err = conn.Send("EXPIRE", keyOfflineOps(mid), int64(d.c.Offline.MaxExpire()/time.Second)); err != nil {
+		log.Errorf("conn.Send(EXPIRE %d) error(%v)", mid, err)
+		return
+	}
+	if err = conn.Flush(); err != nil {
+		log.Errorf("conn.Flush() error(%v)", err)
+		return
+	}
+	for i := 0; i < 5; i++ {
+		if _, err = conn.Receive(); err != nil {
+			log.Errorf("conn.Receive() error(%v)", err)
+			return
+		}
+	}
+	return
+}
+
+// *取出并删除用户所有的离线消息
+func (d *Dao) PopOffline(c context.Context, mid int64) (msgs []*model.OfflineMsg, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	//*集群模式下脚本用到的key都要通过KEYS传入,先取出op集合再把每个op的列表作为KEYS
+	key := keyOfflineOps(mid)
+	ops, err := redis.Int64s(conn.Do("SMEMBERS", key))
+	if err != nil {
+		log.Errorf("conn.Do(SMEMBERS %s) error(%v)", key, err)
+		return
+	}
+	if len(ops) == 0 {
+		return
+	}
+	args := make([]interface{}, 0, len(ops)*2+2)
+	args = append(args, len(ops)+1, key)
+	for _, op := range ops {
+		args = append(args, keyOfflineMsg(mid, int32(op)))
+	}
+	for _, op := range ops {
+		args = append(args, op)
+	}
+	bs, err := redis.ByteSlices(_popOfflineScript.Do(conn, args...))
+	if err != nil {
+		log.Errorf("_popOfflineScript.Do(%d) error(%v)", mid, err)
+		return
+	}
+	for _, b := range bs {
+		msg := new(model.OfflineMsg)
+		if err := json.Unmarshal(b, msg); err != nil {
+			log.Errorf("PopOffline json.Unmarshal(%s) error(%v)", b, err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	sortOffline(msgs)
+	return
+}
+
+// *尝试获取名为name的锁,锁在ttl后自动过期,不需要释放;已被其他实例持有时返回false
+func (d *Dao) TryLock(c context.Context, name, owner string, ttl time.Duration) (ok bool, err error) {
+	conn := d.redis.Get()
+	defer conn.Close()
+	if _, err = redis.String(conn.Do("SET", keyLock(name), owner, "NX", "PX", int64(ttl/time.Millisecond))); err != nil {
+		if err == redis.ErrNil {
+			err = nil
+		} else {
+			log.Errorf("conn.Do(SET %s NX) error(%v)", name, err)
+		}
+		return
+	}
+	ok = true
+	return
+}
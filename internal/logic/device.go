@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"context"
+	"sort"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *按多端登录策略踢掉被新连接顶替的旧连接
+func (l *Logic) enforceDevice(c context.Context, cur *model.KeyInfo, mid int64) {
+	key := cur.Key
+	keyServers, err := l.dao.MidKeyServers(c, []int64{mid})
+	if err != nil {
+		return
+	}
+	var keys []string
+	for k := range keyServers[mid] {
+		if k != key {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	infos, err := l.dao.KeyInfos(c, keys)
+	if err != nil {
+		return
+	}
+	olds := make([]*model.KeyInfo, 0, len(keys))
+	for _, k := range keys {
+		info, ok := infos[k]
+		if !ok {
+			info = &model.KeyInfo{Key: k}
+		}
+		info.Server = keyServers[mid][k]
+		olds = append(olds, info)
+	}
+	kickKeys := make(map[string][]string)
+	for _, info := range displacedKeys(l.c.Device, cur, olds) {
+		kickKeys[info.Server] = append(kickKeys[info.Server], info.Key)
+	}
+	if len(kickKeys) == 0 {
+		return
+	}
+	log.Infof("device limit mid:%d key:%s platform:%s kick:%v", mid, key, cur.Platform, kickKeys)
+	if err = l.kick(c, kickKeys, protocol.KickReasonReplaced); err != nil {
+		log.Errorf("l.kick(%d,%v) error(%v)", mid, kickKeys, err)
+	}
+}
+
+// *计算新连接cur登录后需要踢掉的旧连接,先按平台分组限制,再按总数限制,都从最早登录的开始踢。
+// *同一用户的两个连接同时登录时,双方都会读到对方: 把cur也放进来按(登录时间,key)排序,
+// *两边算出的结果一致,只有较新的连接踢掉较旧的,cur自己排在被踢的位置时不踢别人也不踢自己,由对方踢掉。
+// *双方都还没读到对方的KeyInfo时都不会踢,在线连接数会暂时超出限制,直到下一次登录
+func displacedKeys(d *conf.Device, cur *model.KeyInfo, olds []*model.KeyInfo) (res []*model.KeyInfo) {
+	all := append([]*model.KeyInfo{cur}, olds...)
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Connected != all[j].Connected {
+			return all[i].Connected < all[j].Connected
+		}
+		return all[i].Key < all[j].Key
+	})
+	kicked := make(map[*model.KeyInfo]bool)
+	//*在infos中还没被踢的连接里,从最早的开始踢掉超过max的部分
+	limit := func(infos []*model.KeyInfo, max int) {
+		var alive []*model.KeyInfo
+		for _, info := range infos {
+			if !kicked[info] {
+				alive = append(alive, info)
+			}
+		}
+		for i := 0; i < len(alive)-max; i++ {
+			kicked[alive[i]] = true
+		}
+	}
+	if g := d.Group(cur.Platform); g != nil && g.Max > 0 {
+		var same []*model.KeyInfo
+		for _, info := range all {
+			if d.Group(info.Platform) == g {
+				same = append(same, info)
+			}
+		}
+		limit(same, g.Max)
+	}
+	if d.Max > 0 {
+		limit(all, d.Max)
+	}
+	for _, info := range all {
+		if kicked[info] && info != cur {
+			res = append(res, info)
+		}
+	}
+	return
+}
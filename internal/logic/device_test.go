@@ -0,0 +1,88 @@
+package logic
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+func TestDisplacedKeys(t *testing.T) {
+	groups := &conf.Device{
+		Max: 5,
+		Groups: []*conf.DeviceGroup{
+			{Name: "mobile", Platforms: []string{"ios", "android"}, Max: 1},
+			{Name: "web", Platforms: []string{"web"}, Max: 1},
+		},
+	}
+	info := func(key, platform string, connected int64) *model.KeyInfo {
+		return &model.KeyInfo{Key: key, Platform: platform, Connected: connected}
+	}
+	tests := []struct {
+		name string
+		d    *conf.Device
+		cur  *model.KeyInfo
+		olds []*model.KeyInfo
+		want []string
+	}{
+		{
+			name: "one mobile + one web, new mobile kicks old mobile",
+			d:    groups,
+			cur:  info("c", "android", 3),
+			olds: []*model.KeyInfo{info("a", "ios", 1), info("b", "web", 2)},
+			want: []string{"a"},
+		},
+		{
+			name: "one mobile + one web, new web kicks old web",
+			d:    groups,
+			cur:  info("c", "web", 3),
+			olds: []*model.KeyInfo{info("a", "ios", 1), info("b", "web", 2)},
+			want: []string{"b"},
+		},
+		{
+			name: "one mobile + one web, different group kicks nothing",
+			d:    groups,
+			cur:  info("b", "web", 2),
+			olds: []*model.KeyInfo{info("a", "ios", 1)},
+		},
+		{
+			name: "max N kick oldest",
+			d:    &conf.Device{Max: 3},
+			cur:  info("d", "web", 4),
+			olds: []*model.KeyInfo{info("b", "web", 2), info("a", "ios", 1), info("c", "android", 3)},
+			want: []string{"a"},
+		},
+		{
+			name: "max N kick oldest after group limit",
+			d:    &conf.Device{Max: 2, Groups: groups.Groups},
+			cur:  info("d", "ios", 4),
+			olds: []*model.KeyInfo{info("a", "web", 1), info("b", "pc", 2), info("c", "android", 3)},
+			want: []string{"a", "c"},
+		},
+		{
+			name: "concurrent connect, newer key kicks older",
+			d:    groups,
+			cur:  info("b", "ios", 1),
+			olds: []*model.KeyInfo{info("a", "android", 1)},
+			want: []string{"a"},
+		},
+		{
+			name: "concurrent connect, older key leaves it to the newer",
+			d:    groups,
+			cur:  info("a", "android", 1),
+			olds: []*model.KeyInfo{info("b", "ios", 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, info := range displacedKeys(tt.d, tt.cur, tt.olds) {
+				got = append(got, info.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("displacedKeys() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
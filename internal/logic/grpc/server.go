@@ -9,6 +9,7 @@ import (
 	pb "github.com/gyy0727/mygoim/api/logic"
 	"github.com/gyy0727/mygoim/internal/logic"
 	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/api/protocol"
 	discovery "github.com/gyy0727/mygoim/pkg/discovery"
 	ip "github.com/gyy0727/mygoim/pkg/ip"
 	"google.golang.org/grpc"
@@ -54,7 +55,7 @@ var _ pb.LogicServer = &server{}
 
 // Connect connect a conn.
 func (s *server) Connect(ctx context.Context, req *pb.ConnectReq) (*pb.ConnectReply, error) {
-	mid, key, room, accepts, hb, err := s.srv.Connect(ctx, req.Server, req.Cookie, req.Token)
+	mid, key, room, accepts, hb, err := s.srv.Connect(ctx, req.Server, req.Cookie, req.Ip, req.Token)
 	if err != nil {
 		return &pb.ConnectReply{}, err
 	}
@@ -89,7 +90,7 @@ func (s *server) RenewOnline(ctx context.Context, req *pb.OnlineReq) (*pb.Online
 
 // Receive receive a message.
 func (s *server) Receive(ctx context.Context, req *pb.ReceiveReq) (*pb.ReceiveReply, error) {
-	if err := s.srv.Receive(ctx, req.Mid, req.Proto); err != nil {
+	if err := s.srv.Receive(ctx, req.Mid, req.Key, req.Server, req.Proto); err != nil {
 		return &pb.ReceiveReply{}, err
 	}
 	return &pb.ReceiveReply{}, nil
@@ -100,3 +101,83 @@ func (s *server) Nodes(ctx context.Context, req *pb.NodesReq) (*pb.NodesReply, e
 	// return s.srv.NodesWeighted(ctx, req.Platform, req.ClientIP), nil
 	return nil, nil
 }
+
+// Unacked store unacked messages of a closed conn.
+func (s *server) Unacked(ctx context.Context, req *pb.UnackedReq) (*pb.UnackedReply, error) {
+	if err := s.srv.Unacked(ctx, req.Mid, req.Key, req.Server, req.Protos); err != nil {
+		return &pb.UnackedReply{}, err
+	}
+	return &pb.UnackedReply{}, nil
+}
+
+// Sync return messages after the seq.
+func (s *server) Sync(ctx context.Context, req *pb.SyncReq) (*pb.SyncReply, error) {
+	protos, err := s.srv.Sync(ctx, req.Mid, req.Key, req.Server, req.Seq)
+	if err != nil {
+		return &pb.SyncReply{}, err
+	}
+	return &pb.SyncReply{Protos: protos}, nil
+}
+
+// RoomHistory return recent messages of the room.
+func (s *server) RoomHistory(ctx context.Context, req *pb.RoomHistoryReq) (*pb.RoomHistoryReply, error) {
+	protos, err := s.srv.RoomHistory(ctx, req.Room, int(req.Limit))
+	if err != nil {
+		return &pb.RoomHistoryReply{}, err
+	}
+	return &pb.RoomHistoryReply{Protos: protos}, nil
+}
+
+// SendMsg send a message to another user.
+func (s *server) SendMsg(ctx context.Context, req *pb.SendMsgReq) (*pb.SendMsgReply, error) {
+	if req.Proto == nil {
+		return &pb.SendMsgReply{Status: protocol.SendMsgInvalid}, nil
+	}
+	id, status, err := s.srv.SendMsg(ctx, req.Mid, req.Key, req.Server, req.Proto.Body)
+	if err != nil {
+		return &pb.SendMsgReply{}, err
+	}
+	return &pb.SendMsgReply{Id: id, Status: status}, nil
+}
+
+// Presence return online status of users.
+func (s *server) Presence(ctx context.Context, req *pb.PresenceReq) (*pb.PresenceReply, error) {
+	res, err := s.srv.Presence(ctx, req.Mids, req.Detail)
+	if err != nil {
+		return &pb.PresenceReply{}, err
+	}
+	reply := &pb.PresenceReply{Presences: make([]*pb.Presence, 0, len(res))}
+	for _, p := range res {
+		pp := &pb.Presence{Mid: p.Mid, Online: p.Online, Count: int32(p.Count), Servers: p.Servers}
+		for _, k := range p.Keys {
+			pp.Keys = append(pp.Keys, &pb.KeyInfo{Key: k.Key, Server: k.Server, Platform: k.Platform, Ip: k.IP, Connected: k.Connected})
+		}
+		reply.Presences = append(reply.Presences, pp)
+	}
+	return reply, nil
+}
+
+// KickKeys close conns of the keys.
+func (s *server) KickKeys(ctx context.Context, req *pb.KickKeysReq) (*pb.KickReply, error) {
+	if err := s.srv.KickKeys(ctx, req.Keys, req.Reason); err != nil {
+		return &pb.KickReply{}, err
+	}
+	return &pb.KickReply{}, nil
+}
+
+// KickMids close all conns of the mids.
+func (s *server) KickMids(ctx context.Context, req *pb.KickMidsReq) (*pb.KickReply, error) {
+	if err := s.srv.KickMids(ctx, req.Mids, req.Reason); err != nil {
+		return &pb.KickReply{}, err
+	}
+	return &pb.KickReply{}, nil
+}
+
+// AuthorizeRoom check whether the user can join the room.
+func (s *server) AuthorizeRoom(ctx context.Context, req *pb.AuthorizeRoomReq) (*pb.AuthorizeRoomReply, error) {
+	code, err := s.srv.AuthorizeRoom(ctx, req.Mid, req.Key, req.Server, req.Room, req.Ticket)
+	if err != nil {
+		return &pb.AuthorizeRoomReply{}, err
+	}
+	return &pb.AuthorizeRoomReply{Allow: code == protocol.RoomAuthOK, Code: code}, nil
+}
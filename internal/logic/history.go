@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *把房间消息追加到房间的历史中
+func (l *Logic) addRoomHistory(c context.Context, typ, roomKey string, op int32, msg []byte) {
+	if l.c.History == nil || !l.c.History.Open {
+		return
+	}
+	size, expire := l.c.History.Policy(typ)
+	if size <= 0 {
+		return
+	}
+	hm := &model.HistoryMsg{Op: op, Msg: msg, Ts: time.Now().UnixNano()}
+	if err := l.dao.AddRoomHistory(c, roomKey, hm, size, expire); err != nil {
+		log.Errorf("l.dao.AddRoomHistory(%s,%d) error(%v)", roomKey, op, err)
+	}
+}
+
+// *返回房间最近的历史消息,limit<=0或超过保留条数时返回全部保留的消息
+func (l *Logic) RoomHistory(c context.Context, roomKey string, limit int) (protos []*protocol.Proto, err error) {
+	if l.c.History == nil || !l.c.History.Open || roomKey == "" {
+		return
+	}
+	typ, _, err := model.DecodeRoomKey(roomKey)
+	if err != nil {
+		return
+	}
+	size, _ := l.c.History.Policy(typ)
+	if size <= 0 {
+		return
+	}
+	if limit <= 0 || limit > size {
+		limit = size
+	}
+	msgs, err := l.dao.RoomHistory(c, roomKey, limit)
+	if err != nil {
+		return
+	}
+	for _, msg := range msgs {
+		protos = append(protos, &protocol.Proto{Ver: 1, Op: msg.Op, Body: msg.Msg})
+	}
+	return
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// *踢掉指定key的连接
+func (s *Server) kickKeys(c *gin.Context) {
+	var arg struct {
+		Keys   []string `form:"keys" binding:"required"`
+		Reason int32    `form:"reason"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.KickKeys(c, arg.Keys, arg.Reason); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
+
+// *踢掉指定用户的所有连接
+func (s *Server) kickMids(c *gin.Context) {
+	var arg struct {
+		Mids   []int64 `form:"mids" binding:"required"`
+		Reason int32   `form:"reason"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.KickMids(c, arg.Mids, arg.Reason); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
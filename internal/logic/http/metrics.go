@@ -0,0 +1,43 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricNamespace = "goim"
+	metricSubsystem = "logic"
+)
+
+var (
+	// *http接口的耗时
+	metricHTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "http_duration_seconds",
+		Help:      "HTTP API latency by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path"})
+	// *http接口的请求数,按http状态码和业务错误码统计
+	metricHTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: metricSubsystem,
+		Name:      "http_requests_total",
+		Help:      "HTTP API requests by route, status code and error code.",
+	}, []string{"method", "path", "code", "ecode"})
+)
+
+func init() {
+	prometheus.MustRegister(metricHTTPDuration, metricHTTPRequests)
+}
+
+// *记录一次http请求,path使用路由模板,未匹配路由的请求统一记为unknown
+func observeHTTP(method, path string, code, ecode int, seconds float64) {
+	if path == "" {
+		path = "unknown"
+	}
+	metricHTTPDuration.WithLabelValues(method, path).Observe(seconds)
+	metricHTTPRequests.WithLabelValues(method, path, strconv.Itoa(code), strconv.Itoa(ecode)).Inc()
+}
@@ -32,6 +32,7 @@ func loggerHandler(c *gin.Context) {
 	ecode := c.GetInt(contextErrCode)
 	//*获取客户端ip
 	clientIP := c.ClientIP()
+	observeHTTP(method, c.FullPath(), statusCode, ecode, latency.Seconds())
 	if raw != "" {
 		path = path + "?" + raw
 	}
@@ -48,3 +48,7 @@ func (s *Server) onlineTotal(c *gin.Context) {
 	}
 	result(c, res, OK)
 }
+
+func (s *Server) onlineServers(c *gin.Context) {
+	result(c, s.logic.OnlineServers(c), OK)
+}
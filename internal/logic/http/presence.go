@@ -0,0 +1,74 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// *批量查询用户的在线状态
+func (s *Server) presence(c *gin.Context) {
+	var arg struct {
+		Mids   []int64 `form:"mids" binding:"required"`
+		Detail bool    `form:"detail"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	res, err := s.logic.Presence(c, arg.Mids, arg.Detail)
+	if err != nil {
+		result(c, nil, ServerErr)
+		return
+	}
+	result(c, res, OK)
+}
+
+// *关注用户的在线状态
+func (s *Server) presenceWatch(c *gin.Context) {
+	var arg struct {
+		Mid     int64   `form:"mid" binding:"required"`
+		Targets []int64 `form:"targets" binding:"required"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.Watch(c, arg.Mid, arg.Targets); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
+
+// *取消关注用户的在线状态
+func (s *Server) presenceUnwatch(c *gin.Context) {
+	var arg struct {
+		Mid     int64   `form:"mid" binding:"required"`
+		Targets []int64 `form:"targets" binding:"required"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.Unwatch(c, arg.Mid, arg.Targets); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
+
+// *获取用户关注的所有用户
+func (s *Server) presenceWatching(c *gin.Context) {
+	var arg struct {
+		Mid int64 `form:"mid" binding:"required"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	mids, err := s.logic.Watching(c, arg.Mid)
+	if err != nil {
+		result(c, nil, ServerErr)
+		return
+	}
+	result(c, mids, OK)
+}
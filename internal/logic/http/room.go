@@ -0,0 +1,97 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+const (
+	// *单次查询房间成员的最大条数
+	_maxRoomMembers = 100
+	// *房间成员分页的最大偏移量,每个comet都要返回offset+limit条,偏移量太大时代价过高
+	_maxRoomOffset = 10000
+)
+
+// *把用户加入房间的允许名单
+func (s *Server) roomAllow(c *gin.Context) {
+	var arg struct {
+		Type string  `form:"type" binding:"required"`
+		Room string  `form:"room" binding:"required"`
+		Mids []int64 `form:"mids" binding:"required"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.AddRoomAllow(c, model.EncodeRoomKey(arg.Type, arg.Room), arg.Mids); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
+
+// *把用户移出房间的允许名单
+func (s *Server) roomDisallow(c *gin.Context) {
+	var arg struct {
+		Type string  `form:"type" binding:"required"`
+		Room string  `form:"room" binding:"required"`
+		Mids []int64 `form:"mids" binding:"required"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.DelRoomAllow(c, model.EncodeRoomKey(arg.Type, arg.Room), arg.Mids); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
+
+// *设置房间的密码,密码为空时取消
+func (s *Server) roomPassword(c *gin.Context) {
+	var arg struct {
+		Type     string `form:"type" binding:"required"`
+		Room     string `form:"room" binding:"required"`
+		Password string `form:"password"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if err := s.logic.SetRoomPassword(c, model.EncodeRoomKey(arg.Type, arg.Room), arg.Password); err != nil {
+		errors(c, ServerErr, err.Error())
+		return
+	}
+	result(c, nil, OK)
+}
+
+// *分页查询房间中的连接
+func (s *Server) roomMembers(c *gin.Context) {
+	var arg struct {
+		Type   string `form:"type" binding:"required"`
+		Room   string `form:"room" binding:"required"`
+		Offset int    `form:"offset"`
+		Limit  int    `form:"limit"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if arg.Offset < 0 {
+		arg.Offset = 0
+	}
+	if arg.Offset > _maxRoomOffset {
+		errors(c, RequestErr, "offset too large")
+		return
+	}
+	if arg.Limit <= 0 || arg.Limit > _maxRoomMembers {
+		arg.Limit = _maxRoomMembers
+	}
+	res, err := s.logic.RoomMembers(c, model.EncodeRoomKey(arg.Type, arg.Room), arg.Offset, arg.Limit)
+	if err != nil {
+		result(c, nil, ServerErr)
+		return
+	}
+	result(c, res, OK)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gyy0727/mygoim/internal/logic"
 	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Server is http server.
@@ -30,6 +31,7 @@ func New(c *conf.HTTPServer, l *logic.Logic) *Server {
 }
 
 func (s *Server) initRouter() {
+	s.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	group := s.engine.Group("/goim")
 	group.POST("/push/keys", s.pushKeys)
 	group.POST("/push/mids", s.pushMids)
@@ -38,6 +40,19 @@ func (s *Server) initRouter() {
 	group.GET("/online/top", s.onlineTop)
 	group.GET("/online/room", s.onlineRoom)
 	group.GET("/online/total", s.onlineTotal)
+	group.GET("/online/servers", s.onlineServers)
+	group.GET("/presence", s.presence)
+	group.GET("/presence/watch", s.presenceWatching)
+	group.POST("/presence/watch", s.presenceWatch)
+	group.POST("/presence/unwatch", s.presenceUnwatch)
+	group.POST("/kick/keys", s.kickKeys)
+	group.POST("/kick/mids", s.kickMids)
+	group.POST("/room/allow", s.roomAllow)
+	group.POST("/room/disallow", s.roomDisallow)
+	group.POST("/room/password", s.roomPassword)
+	group.GET("/room/members", s.roomMembers)
+	group.POST("/whitelist/add", s.whitelistAdd)
+	group.POST("/whitelist/del", s.whitelistDel)
 	// group.GET("/nodes/weighted", s.nodesWeighted)
 	// group.GET("/nodes/instances", s.nodesInstances)
 }
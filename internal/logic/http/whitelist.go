@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// *向所有comet添加白名单,ttl为秒,添加key前缀时必须带上1-100的采样百分比sample
+func (s *Server) whitelistAdd(c *gin.Context) {
+	var arg struct {
+		Mids     []int64  `form:"mids"`
+		Prefixes []string `form:"prefixes"`
+		Sample   int32    `form:"sample"`
+		TTL      int64    `form:"ttl"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if len(arg.Mids) == 0 && len(arg.Prefixes) == 0 {
+		errors(c, RequestErr, "mids or prefixes is required")
+		return
+	}
+	if len(arg.Prefixes) > 0 && (arg.Sample < 1 || arg.Sample > 100) {
+		errors(c, RequestErr, "sample must be between 1 and 100")
+		return
+	}
+	result(c, map[string]interface{}{"servers": s.logic.AddWhitelist(c, arg.Mids, arg.Prefixes, arg.Sample, arg.TTL)}, OK)
+}
+
+// *从所有comet移除白名单
+func (s *Server) whitelistDel(c *gin.Context) {
+	var arg struct {
+		Mids     []int64  `form:"mids"`
+		Prefixes []string `form:"prefixes"`
+	}
+	if err := c.BindQuery(&arg); err != nil {
+		errors(c, RequestErr, err.Error())
+		return
+	}
+	if len(arg.Mids) == 0 && len(arg.Prefixes) == 0 {
+		errors(c, RequestErr, "mids or prefixes is required")
+		return
+	}
+	result(c, map[string]interface{}{"servers": s.logic.DelWhitelist(c, arg.Mids, arg.Prefixes)}, OK)
+}
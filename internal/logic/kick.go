@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"context"
+
+	log "github.com/golang/glog"
+)
+
+// *踢掉指定key的连接
+func (l *Logic) KickKeys(c context.Context, keys []string, reason int32) (err error) {
+	servers, err := l.dao.ServersByKeys(c, keys)
+	if err != nil {
+		return
+	}
+	kickKeys := make(map[string][]string)
+	for i, key := range keys {
+		if server := servers[i]; server != "" && key != "" {
+			kickKeys[server] = append(kickKeys[server], key)
+		}
+	}
+	return l.kick(c, kickKeys, reason)
+}
+
+// *踢掉指定用户的所有连接
+func (l *Logic) KickMids(c context.Context, mids []int64, reason int32) (err error) {
+	keyServers, _, err := l.dao.KeysByMids(c, mids)
+	if err != nil {
+		return
+	}
+	kickKeys := make(map[string][]string)
+	for key, server := range keyServers {
+		if key == "" || server == "" {
+			continue
+		}
+		kickKeys[server] = append(kickKeys[server], key)
+	}
+	return l.kick(c, kickKeys, reason)
+}
+
+// *按comet分组投递踢人消息
+func (l *Logic) kick(c context.Context, kickKeys map[string][]string, reason int32) (err error) {
+	for server, keys := range kickKeys {
+		if err = l.dao.KickKeys(c, server, keys, reason); err != nil {
+			return
+		}
+		log.Infof("kick server:%s keys:%v reason:%d", server, keys, reason)
+	}
+	return
+}
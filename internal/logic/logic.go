@@ -2,6 +2,7 @@ package logic
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	log "github.com/golang/glog"
@@ -16,6 +17,7 @@ import (
 const (
 	_onlineTick     = time.Second * 10 //*在线状态检查的时间间隔（10 秒）
 	_onlineDeadline = time.Minute * 5  //*在线状态的超时时间（5 分钟）
+	_statsExpire    = 3                //*comet统计失败时沿用上一次的结果,超过该次数的检查间隔后丢弃
 )
 
 type Logic struct {
@@ -27,7 +29,14 @@ type Logic struct {
 	roomCount  map[string]int32        //*房间在线人数统计
 	// nodes      []*discovery.Node    //*节点列表
 	// loadBalancer *LoadBalancer      //*负载均衡器
-	regions map[string]string //*省份到区域的映射
+	regions      map[string]string    //*省份到区域的映射
+	offline      dao.OfflineStore     //*离线消息存储,未开启时为nil
+	auth         Authenticator        //*连接认证
+	cometClients cometClients         //*直接调用comet的rpc客户端
+	cometStats   []*model.CometStats  //*每个comet的统计,按serverID排序
+	statsMutex   sync.RWMutex         //*cometStats的锁
+	reloadMutex  sync.RWMutex         //*热加载时替换regions和auth的锁
+	presenceChs  []chan *presenceTask //*按mid分片的上下线事件队列
 }
 
 func New(c *conf.Config) (l *Logic) {
@@ -36,13 +45,24 @@ func New(c *conf.Config) (l *Logic) {
 		dao: dao.New(c),
 		dis: discovery.EResolver,
 		// loadBalancer: NewLoadBalancer(),
-		regions: make(map[string]string),
 	}
-	l.dis.SetTargetNode("goim.comet")
-	l.initRegions()
+	if c.Offline != nil && c.Offline.Open {
+		l.offline = dao.NewOfflineStore(c.Offline, l.dao)
+	}
+	var err error
+	if l.auth, err = NewAuthenticator(c.Auth, l.dao); err != nil {
+		panic(err)
+	}
+	l.initPresence(c.Presence)
+	l.dis.SetTargetNode(_cometAppID)
+	l.regions = newRegions(c.Regions)
 	// l.initNodes()
 	_ = l.loadOnline()
 	go l.onlineproc()
+	go l.statsproc()
+	if c.Rebalance != nil && c.Rebalance.Open {
+		go l.rebalanceproc()
+	}
 	return l
 }
 
@@ -56,13 +76,55 @@ func (l *Logic) Close() {
 	l.dao.Close()
 }
 
-// *根据配置初始化省份到区域的映射关系
-func (l *Logic) initRegions() {
-	for region, ps := range l.c.Regions {
+// *根据配置生成省份到区域的映射关系
+func newRegions(c map[string][]string) map[string]string {
+	regions := make(map[string]string)
+	for region, ps := range c {
 		for _, province := range ps {
-			l.regions[province] = region
+			regions[province] = region
 		}
 	}
+	return regions
+}
+
+// *热加载区域映射、重试策略和认证配置,nc需要先通过Validate校验,新的认证配置无效时不做任何修改。
+// *Node配置在本仓库中还没有启用(见nodes.go),没有可以热加载的节点配置
+func (l *Logic) Reload(nc *conf.Config) error {
+	auth, err := NewAuthenticator(nc.Auth, l.dao)
+	if err != nil {
+		return err
+	}
+	regions := newRegions(nc.Regions)
+	l.reloadMutex.Lock()
+	l.auth = auth
+	l.regions = regions
+	l.c.Auth = nc.Auth
+	l.c.Regions = nc.Regions
+	l.c.Backoff = nc.Backoff
+	l.reloadMutex.Unlock()
+	log.Infof("config reloaded auth:%s regions:%d backoff:%+v", nc.Auth.Type, len(regions), *nc.Backoff)
+	return nil
+}
+
+// *返回省份所属的区域
+func (l *Logic) region(province string) string {
+	l.reloadMutex.RLock()
+	defer l.reloadMutex.RUnlock()
+	return l.regions[province]
+}
+
+// *返回当前的重试策略
+func (l *Logic) backoff() *conf.Backoff {
+	l.reloadMutex.RLock()
+	defer l.reloadMutex.RUnlock()
+	return l.c.Backoff
+}
+
+// *返回当前的认证器
+func (l *Logic) authenticator() Authenticator {
+	l.reloadMutex.RLock()
+	defer l.reloadMutex.RUnlock()
+	return l.auth
 }
 
 // func (l *Logic) initNodes() {
@@ -146,7 +208,7 @@ func (l *Logic) loadOnline() (err error) {
 	var (
 		roomCount = make(map[string]int32)
 	)
-	nodes := l.dis.GetServiceNodes("goim.comet")
+	nodes := l.dis.GetServiceNodes(_cometAppID)
 	for _, server := range nodes {
 		//* // 定义一个变量，用于存储从数据库获取的在线信息
 		var online *model.Online
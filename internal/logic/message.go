@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+
+	log "github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *发送点对点消息,校验后通过PushMids投递给目标用户,返回消息ID和发送状态
+func (l *Logic) SendMsg(c context.Context, mid int64, key, server string, body []byte) (id string, status int32, err error) {
+	var arg model.SendMsg
+	if err = json.Unmarshal(body, &arg); err != nil {
+		log.Warningf("sendmsg json.Unmarshal(%s) mid:%d error(%v)", body, mid, err)
+		return "", protocol.SendMsgInvalid, nil
+	}
+	if mid <= 0 || arg.To <= 0 || arg.To == mid || len(arg.Msg) == 0 {
+		log.Warningf("sendmsg invalid from:%d to:%d key:%s", mid, arg.To, key)
+		return "", protocol.SendMsgInvalid, nil
+	}
+	id = uuid.New().String()
+	msg, err := json.Marshal(&model.Message{ID: id, From: mid, Msg: arg.Msg})
+	if err != nil {
+		return "", protocol.SendMsgFailed, err
+	}
+	if err = l.PushMids(c, protocol.OpSendMsg, []int64{arg.To}, msg); err != nil {
+		log.Errorf("sendmsg l.PushMids(%d,%d) error(%v)", mid, arg.To, err)
+		return id, protocol.SendMsgFailed, err
+	}
+	log.Infof("sendmsg id:%s from:%d to:%d key:%s server:%s", id, mid, arg.To, key, server)
+	return id, protocol.SendMsgOK, nil
+}
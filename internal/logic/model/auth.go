@@ -0,0 +1,11 @@
+package model
+
+// *AuthToken 表示连接认证通过后得到的用户信息。
+type AuthToken struct {
+	Mid      int64   `json:"mid"`          //*用户 ID
+	Key      string  `json:"key"`          //*连接的唯一标识,为空时由logic生成
+	RoomID   string  `json:"room_id"`      //*连接后加入的房间
+	Platform string  `json:"platform"`     //*客户端平台
+	Accepts  []int32 `json:"accepts"`      //*订阅的操作码
+	Ts       int64   `json:"ts,omitempty"` //*签发时间，使用 Unix 时间戳表示,仅hmac认证使用
+}
@@ -0,0 +1,8 @@
+package model
+
+// *HistoryMsg 表示一条保存在房间历史中的消息。
+type HistoryMsg struct {
+	Op  int32  `json:"op"`  //*消息的操作码
+	Msg []byte `json:"msg"` //*消息体
+	Ts  int64  `json:"ts"`  //*写入时间，使用 Unix 时间戳表示
+}
@@ -0,0 +1,16 @@
+package model
+
+import "encoding/json"
+
+// *SendMsg 表示客户端通过 OpSendMsg 发送的点对点消息。
+type SendMsg struct {
+	To  int64           `json:"to"`  //*目标用户 ID
+	Msg json.RawMessage `json:"msg"` //*消息内容,原样投递给目标用户
+}
+
+// *Message 表示投递给目标用户的点对点消息。
+type Message struct {
+	ID   string          `json:"id"`   //*服务端分配的消息 ID
+	From int64           `json:"from"` //*发送者的用户 ID
+	Msg  json.RawMessage `json:"msg"`  //*消息内容
+}
@@ -0,0 +1,9 @@
+package model
+
+// *OfflineMsg 表示一条因用户不在线而暂存的消息。
+type OfflineMsg struct {
+	Op  int32  `json:"op"`  //*消息的操作码
+	Msg []byte `json:"msg"` //*消息体
+	Seq int32  `json:"seq"` //*消息的序列号,未分配时为0
+	Ts  int64  `json:"ts"`  //*写入时间，使用 Unix 时间戳表示
+}
@@ -15,3 +15,28 @@ type Top struct {
 	RoomID string `json:"room_id"` //*房间 ID，表示唯一标识一个房间
 	Count  int32  `json:"count"`   //*统计数量，表示该房间的某种统计值（如在线人数、消息数量等）
 }
+
+//*BucketStats 表示 comet 上单个 bucket 的统计。
+type BucketStats struct {
+	Channels int32 `json:"channels"` //*连接数
+	Rooms    int32 `json:"rooms"`    //*有在线连接的房间数
+	IPs      int32 `json:"ips"`      //*不同的 IP 数
+}
+
+//*CometStats 表示单个 comet 的连接、房间和运行时统计。
+type CometStats struct {
+	Server        string         `json:"server"`         //*comet 的 serverID
+	Channels      int64          `json:"channels"`       //*连接总数
+	Rooms         int64          `json:"rooms"`          //*有在线连接的房间数
+	IPs           int64          `json:"ips"`            //*不同的 IP 数
+	SignalDropped uint64         `json:"signal_dropped"` //*信号通道已满被丢弃的消息数
+	Goroutines    int32          `json:"goroutines"`     //*goroutine 数量
+	HeapAlloc     uint64         `json:"heap_alloc"`     //*堆上已分配的字节数
+	HeapInuse     uint64         `json:"heap_inuse"`     //*堆上正在使用的字节数
+	Sys           uint64         `json:"sys"`            //*从系统申请的字节数
+	NumGC         uint32         `json:"num_gc"`         //*GC 次数
+	StartTime     int64          `json:"start_time"`     //*启动时间，使用 Unix 时间戳表示
+	Buckets       []*BucketStats `json:"buckets"`        //*每个 bucket 的统计
+	Updated       int64          `json:"updated"`        //*最后更新时间，使用 Unix 时间戳表示
+	Stale         bool           `json:"stale"`          //*最近一次统计失败,沿用上一次的结果
+}
@@ -0,0 +1,26 @@
+package model
+
+// *KeyInfo 表示用户一个连接的元数据,在 Connect 时记录。
+type KeyInfo struct {
+	Key       string `json:"key"`       //*连接的唯一标识
+	Server    string `json:"server"`    //*连接所在的 comet
+	Platform  string `json:"platform"`  //*客户端平台
+	IP        string `json:"ip"`        //*客户端 IP
+	Connected int64  `json:"connected"` //*连接时间，使用 Unix 时间戳表示
+}
+
+// *Presence 表示用户的在线状态。
+type Presence struct {
+	Mid     int64      `json:"mid"`            //*用户 ID
+	Online  bool       `json:"online"`         //*是否在线
+	Count   int        `json:"count"`          //*在线的连接数
+	Servers []string   `json:"servers"`        //*连接所在的 comet 列表
+	Keys    []*KeyInfo `json:"keys,omitempty"` //*每个连接的详情,仅在查询详情时返回
+}
+
+// *PresenceEvent 表示用户的上下线事件,推送给关注该用户的用户。
+type PresenceEvent struct {
+	Mid    int64 `json:"mid"`    //*用户 ID
+	Online bool  `json:"online"` //*true为上线,false为下线
+	Ts     int64 `json:"ts"`     //*事件时间，使用毫秒时间戳表示
+}
@@ -5,6 +5,20 @@ import (
 	"net/url"
 )
 
+// *RoomMember 表示房间中的一个连接。
+type RoomMember struct {
+	Mid    int64  `json:"mid"`    //*用户 ID
+	Key    string `json:"key"`    //*连接的唯一标识
+	Server string `json:"server"` //*连接所在的 comet
+}
+
+// *RoomMembers 表示分页查询的房间成员。
+type RoomMembers struct {
+	Total   int           `json:"total"`   //*房间中的连接总数,不包含查询失败的 comet
+	Members []*RoomMember `json:"members"` //*当前页的连接,按 key 排序
+	Failed  []string      `json:"failed"`  //*查询失败的 comet,不为空时结果不完整
+}
+
 // *EncodeRoomKey 将房间类型和房间 ID 编码为一个房间键。
 // *房间键的格式为 "类型://房间ID"。
 func EncodeRoomKey(typ string, room string) string {
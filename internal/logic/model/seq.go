@@ -0,0 +1,8 @@
+package model
+
+// *SeqMsg 表示一条分配了序列号、可供客户端同步的消息。
+type SeqMsg struct {
+	Seq int32  `json:"seq"` //*消息的序列号
+	Op  int32  `json:"op"`  //*消息的操作码
+	Msg []byte `json:"msg"` //*消息体
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *为不在线的用户保存离线消息
+func (l *Logic) storeOffline(c context.Context, mid int64, op, seq int32, msg []byte) {
+	if l.offline == nil {
+		return
+	}
+	max, expire := l.c.Offline.Policy(op)
+	om := &model.OfflineMsg{Op: op, Msg: msg, Seq: seq, Ts: time.Now().UnixNano()}
+	if err := l.offline.AddOffline(c, mid, om, max, expire); err != nil {
+		log.Errorf("l.offline.AddOffline(%d,%d) error(%v)", mid, op, err)
+	}
+}
+
+// *用户上线后把离线消息推送到刚建立的连接
+func (l *Logic) replayOffline(c context.Context, mid int64, key, server string) {
+	msgs, err := l.offline.PopOffline(c, mid)
+	if err != nil {
+		log.Errorf("l.offline.PopOffline(%d) error(%v)", mid, err)
+		return
+	}
+	for i, msg := range msgs {
+		if err = l.dao.PushMsg(c, msg.Op, msg.Seq, server, []string{key}, msg.Msg); err != nil {
+			log.Errorf("l.dao.PushMsg(%d,%s,%s) error(%v)", mid, key, server, err)
+			//*推送失败的消息重新存回去,下次上线再推
+			for _, m := range msgs[i:] {
+				max, expire := l.c.Offline.Policy(m.Op)
+				if err = l.offline.AddOffline(c, mid, m, max, expire); err != nil {
+					log.Errorf("l.offline.AddOffline(%d,%d) error(%v)", mid, m.Op, err)
+				}
+			}
+			return
+		}
+	}
+	if len(msgs) > 0 {
+		log.Infof("replay offline mid:%d key:%s server:%s count:%d", mid, key, server, len(msgs))
+	}
+}
+
+// *comet在连接断开时交回的未确认消息,存为离线消息等用户下次上线再推
+func (l *Logic) Unacked(c context.Context, mid int64, key, server string, protos []*protocol.Proto) (err error) {
+	if l.offline == nil || mid == 0 {
+		log.Warningf("drop unacked mid:%d key:%s server:%s count:%d", mid, key, server, len(protos))
+		return
+	}
+	now := time.Now().UnixNano()
+	for i, p := range protos {
+		max, expire := l.c.Offline.Policy(p.Op)
+		om := &model.OfflineMsg{Op: p.Op, Msg: p.Body, Seq: p.Seq, Ts: now + int64(i)}
+		if err = l.offline.AddOffline(c, mid, om, max, expire); err != nil {
+			log.Errorf("l.offline.AddOffline(%d,%d) error(%v)", mid, p.Op, err)
+			return
+		}
+	}
+	log.Infof("store unacked mid:%d key:%s server:%s count:%d", mid, key, server, len(protos))
+	return
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gyy0727/mygoim/api/comet"
 	"github.com/gyy0727/mygoim/internal/logic/model"
 )
 
@@ -48,7 +53,83 @@ func (l *Logic) OnlineRoom(c context.Context, typ string, rooms []string) (res m
 	return
 }
 
-//*获取总的在线 IP 数和连接数
+//*获取总的在线 IP 数和连接数,由 statsproc 定期汇总
 func (l *Logic) OnlineTotal(c context.Context) (int64, int64) {
-	return l.totalIPs, l.totalConns
+	return atomic.LoadInt64(&l.totalIPs), atomic.LoadInt64(&l.totalConns)
+}
+
+//*获取每个 comet 的统计
+func (l *Logic) OnlineServers(c context.Context) []*model.CometStats {
+	l.statsMutex.RLock()
+	defer l.statsMutex.RUnlock()
+	return l.cometStats
+}
+
+//*定期汇总所有 comet 的统计
+func (l *Logic) statsproc() {
+	for {
+		l.loadStats(context.Background())
+		time.Sleep(_onlineTick)
+	}
+}
+
+//*调用所有 comet 的 Stats 接口,汇总总连接数和 IP 数;
+//*某个 comet 偶尔失败时沿用它上一次的统计并标记为 Stale,避免总数抖动,超过 _statsExpire 个检查间隔后丢弃
+func (l *Logic) loadStats(c context.Context) {
+	var (
+		mutex  sync.Mutex
+		stats  = make([]*model.CometStats, 0)
+		failed = make(map[string]struct{})
+		now    = time.Now().Unix()
+	)
+	l.eachComet(c, func(ctx context.Context, server string, client comet.CometClient) error {
+		reply, err := client.Stats(ctx, &comet.StatsReq{})
+		if err != nil {
+			mutex.Lock()
+			failed[server] = struct{}{}
+			mutex.Unlock()
+			return err
+		}
+		st := &model.CometStats{
+			Server:        server,
+			Channels:      reply.Channels,
+			Rooms:         reply.Rooms,
+			IPs:           reply.Ips,
+			SignalDropped: reply.SignalDropped,
+			Goroutines:    reply.Goroutines,
+			HeapAlloc:     reply.HeapAlloc,
+			HeapInuse:     reply.HeapInuse,
+			Sys:           reply.Sys,
+			NumGC:         reply.NumGC,
+			StartTime:     reply.StartTime,
+			Buckets:       make([]*model.BucketStats, 0, len(reply.Buckets)),
+			Updated:       now,
+		}
+		for _, b := range reply.Buckets {
+			st.Buckets = append(st.Buckets, &model.BucketStats{Channels: b.Channels, Rooms: b.Rooms, IPs: b.Ips})
+		}
+		mutex.Lock()
+		stats = append(stats, st)
+		mutex.Unlock()
+		return nil
+	})
+	expire := now - int64(_statsExpire*_onlineTick/time.Second)
+	for _, st := range l.OnlineServers(c) {
+		if _, ok := failed[st.Server]; ok && st.Updated >= expire {
+			old := *st
+			old.Stale = true
+			stats = append(stats, &old)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Server < stats[j].Server })
+	var ips, conns int64
+	for _, st := range stats {
+		ips += st.IPs
+		conns += st.Channels
+	}
+	atomic.StoreInt64(&l.totalIPs, ips)
+	atomic.StoreInt64(&l.totalConns, conns)
+	l.statsMutex.Lock()
+	l.cometStats = stats
+	l.statsMutex.Unlock()
 }
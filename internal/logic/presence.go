@@ -0,0 +1,147 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	log "github.com/golang/glog"
+	pb "github.com/gyy0727/mygoim/api/logic"
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *批量查询用户的在线状态,detail为true时返回每个连接的详情
+func (l *Logic) Presence(c context.Context, mids []int64, detail bool) (res []*model.Presence, err error) {
+	midKeys, err := l.dao.MidKeyServers(c, mids)
+	if err != nil {
+		return
+	}
+	var infos map[string]*model.KeyInfo
+	if detail {
+		var keys []string
+		for _, keyServers := range midKeys {
+			for key := range keyServers {
+				keys = append(keys, key)
+			}
+		}
+		if infos, err = l.dao.KeyInfos(c, keys); err != nil {
+			return
+		}
+	}
+	res = make([]*model.Presence, 0, len(mids))
+	for _, mid := range mids {
+		var (
+			keyServers = midKeys[mid]
+			servers    = make(map[string]struct{})
+			p          = &model.Presence{Mid: mid, Online: len(keyServers) > 0, Count: len(keyServers), Servers: []string{}}
+		)
+		for key, server := range keyServers {
+			if _, ok := servers[server]; !ok {
+				servers[server] = struct{}{}
+				p.Servers = append(p.Servers, server)
+			}
+			if !detail {
+				continue
+			}
+			info, ok := infos[key]
+			if !ok {
+				info = &model.KeyInfo{Key: key, Server: server}
+			}
+			p.Keys = append(p.Keys, info)
+		}
+		sort.Strings(p.Servers)
+		sort.Slice(p.Keys, func(i, j int) bool { return p.Keys[i].Key < p.Keys[j].Key })
+		res = append(res, p)
+	}
+	return
+}
+
+// *关注用户的在线状态
+func (l *Logic) Watch(c context.Context, mid int64, targets []int64) (err error) {
+	return l.dao.AddWatch(c, mid, targets)
+}
+
+// *取消关注用户的在线状态
+func (l *Logic) Unwatch(c context.Context, mid int64, targets []int64) (err error) {
+	return l.dao.DelWatch(c, mid, targets)
+}
+
+// *获取用户关注的所有用户
+func (l *Logic) Watching(c context.Context, mid int64) (mids []int64, err error) {
+	return l.dao.Watching(c, mid)
+}
+
+// *一次上下线事件
+type presenceTask struct {
+	mid    int64
+	key    string
+	server string
+	online bool
+	ts     int64
+}
+
+// *启动处理上下线事件的协程
+func (l *Logic) initPresence(c *conf.Presence) {
+	if c.Routines <= 0 {
+		c.Routines = 1
+	}
+	l.presenceChs = make([]chan *presenceTask, c.Routines)
+	for i := range l.presenceChs {
+		ch := make(chan *presenceTask, c.Buffer)
+		l.presenceChs[i] = ch
+		go l.presenceproc(ch)
+	}
+}
+
+// *按mid把事件分给固定的协程,保证同一用户的上线和下线按发生顺序通知
+func (l *Logic) presenceChanged(mid int64, key, server string, online bool) {
+	t := &presenceTask{mid: mid, key: key, server: server, online: online, ts: time.Now().UnixNano() / int64(time.Millisecond)}
+	l.presenceChs[uint64(mid)%uint64(len(l.presenceChs))] <- t
+}
+
+func (l *Logic) presenceproc(ch chan *presenceTask) {
+	for t := range ch {
+		l.notifyPresence(context.Background(), t.mid, t.key, t.server, t.online, t.ts)
+	}
+}
+
+// *用户的第一个连接建立或最后一个连接断开时,通知关注者并投递到Kafka
+func (l *Logic) notifyPresence(c context.Context, mid int64, key, server string, online bool, ts int64) {
+	if l.c.Presence.Topic != "" {
+		event := &pb.PresenceEvent{Mid: mid, Online: online, Key: key, Server: server, Ts: ts}
+		if err := l.dao.PushPresence(c, l.c.Presence.Topic, event); err != nil {
+			log.Errorf("l.dao.PushPresence(%d,%v) error(%v)", mid, online, err)
+		}
+	}
+	if !l.c.Presence.Notify {
+		return
+	}
+	watchers, err := l.dao.Watchers(c, mid)
+	if err != nil || len(watchers) == 0 {
+		return
+	}
+	msg, err := json.Marshal(&model.PresenceEvent{Mid: mid, Online: online, Ts: ts})
+	if err != nil {
+		return
+	}
+	midKeys, err := l.dao.MidKeyServers(c, watchers)
+	if err != nil {
+		return
+	}
+	//*上下线通知只推给在线的关注者,不分配序列号也不存离线消息
+	pushKeys := make(map[string][]string)
+	for _, keyServers := range midKeys {
+		for key, server := range keyServers {
+			pushKeys[server] = append(pushKeys[server], key)
+		}
+	}
+	for server, keys := range pushKeys {
+		if err = l.dao.PushMsg(c, protocol.OpPresenceChange, 0, server, keys, msg); err != nil {
+			log.Errorf("l.dao.PushMsg(%d,%s) error(%v)", mid, server, err)
+		}
+	}
+	log.Infof("presence notify mid:%d online:%v watchers:%d", mid, online, len(watchers))
+}
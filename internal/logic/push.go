@@ -12,15 +12,20 @@ func (l *Logic) PushKeys(c context.Context, op int32, keys []string, msg []byte)
 	if err != nil {
 		return
 	}
-	pushKeys := make(map[string][]string)
+	seqs := l.keySeqs(c, op, keys, msg)
+	pushKeys := make(map[pushTarget][]string)
 	for i, key := range keys {
 		server := servers[i]
 		if server != "" && key != "" {
-			pushKeys[server] = append(pushKeys[server], key)
+			t := pushTarget{server: server}
+			if seqs != nil {
+				t.seq = seqs[i]
+			}
+			pushKeys[t] = append(pushKeys[t], key)
 		}
 	}
-	for server := range pushKeys {
-		if err = l.dao.PushMsg(c, op, server, pushKeys[server], msg); err != nil {
+	for t, keys := range pushKeys {
+		if err = l.dao.PushMsg(c, op, t.seq, t.server, keys, msg); err != nil {
 			return
 		}
 	}
@@ -29,20 +34,34 @@ func (l *Logic) PushKeys(c context.Context, op int32, keys []string, msg []byte)
 
 //*根据用户 ID（mids）推送消息到对应的服务器
 func (l *Logic) PushMids(c context.Context, op int32, mids []int64, msg []byte) (err error) {
-	keyServers, _, err := l.dao.KeysByMids(c, mids)
+	midKeys, err := l.dao.MidKeyServers(c, mids)
 	if err != nil {
 		return
 	}
-	keys := make(map[string][]string)
-	for key, server := range keyServers {
-		if key == "" || server == "" {
-			log.Warningf("push key:%s server:%s is empty", key, server)
+	seqs := l.midSeqs(c, op, mids, msg)
+	pushKeys := make(map[pushTarget][]string)
+	seen := make(map[int64]struct{}, len(mids))
+	for _, mid := range mids {
+		if _, ok := seen[mid]; ok {
 			continue
 		}
-		keys[server] = append(keys[server], key)
+		seen[mid] = struct{}{}
+		keyServers, ok := midKeys[mid]
+		if !ok {
+			l.storeOffline(c, mid, op, seqs[mid], msg)
+			continue
+		}
+		for key, server := range keyServers {
+			if key == "" || server == "" {
+				log.Warningf("push key:%s server:%s is empty", key, server)
+				continue
+			}
+			t := pushTarget{server: server, seq: seqs[mid]}
+			pushKeys[t] = append(pushKeys[t], key)
+		}
 	}
-	for server, keys := range keys {
-		if err = l.dao.PushMsg(c, op, server, keys, msg); err != nil {
+	for t, keys := range pushKeys {
+		if err = l.dao.PushMsg(c, op, t.seq, t.server, keys, msg); err != nil {
 			return
 		}
 	}
@@ -51,7 +70,12 @@ func (l *Logic) PushMids(c context.Context, op int32, mids []int64, msg []byte)
 
 //*向指定房间广播消息
 func (l *Logic) PushRoom(c context.Context, op int32, typ, room string, msg []byte) (err error) {
-	return l.dao.BroadcastRoomMsg(c, op, model.EncodeRoomKey(typ, room), msg)
+	roomKey := model.EncodeRoomKey(typ, room)
+	if err = l.dao.BroadcastRoomMsg(c, op, roomKey, msg); err != nil {
+		return
+	}
+	l.addRoomHistory(c, typ, roomKey, op, msg)
+	return
 }
 
 //*向所有用户广播消息
@@ -0,0 +1,174 @@
+package logic
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/comet"
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+	discovery "github.com/gyy0727/mygoim/pkg/discovery"
+)
+
+// *再均衡的redis锁名,锁的有效期为一个检查间隔
+const _rebalanceLock = "rebalance"
+
+// *一个comet的负载,来自服务发现的元数据
+type cometLoad struct {
+	server string   //*comet的serverID
+	addrs  []string //*客户端连接用的公网地址
+	conns  int64    //*当前连接数
+	weight int64    //*负载均衡权重,<=0时按1计算
+}
+
+// *一次转移: 让from转移count个连接到to
+type shedTask struct {
+	from  string
+	to    *cometLoad
+	count int
+}
+
+// *定期检查各comet的连接数,让过载的comet把一部分连接转移到负载最低的comet
+func (l *Logic) rebalanceproc() {
+	for {
+		time.Sleep(time.Duration(l.c.Rebalance.Interval))
+		l.rebalance(context.Background())
+	}
+}
+
+// *集群健康时按计划调用过载comet的Shed接口,多个logic实例通过redis锁保证每个间隔内只有一个实例执行
+func (l *Logic) rebalance(c context.Context) {
+	locked, err := l.dao.TryLock(c, _rebalanceLock, l.c.Env.Host, time.Duration(l.c.Rebalance.Interval))
+	if err != nil || !locked {
+		return
+	}
+	loads, ok := l.cometLoads(c)
+	if !ok {
+		return
+	}
+	tasks := planRebalance(loads, l.c.Rebalance)
+	if len(tasks) == 0 {
+		return
+	}
+	clients := l.comets()
+	for _, t := range tasks {
+		client, ok := clients[t.from]
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(c, time.Duration(l.c.RPCClient.Timeout))
+		reply, err := client.Shed(ctx, &comet.ShedReq{Count: int32(t.count), Server: t.to.server, Addrs: t.to.addrs})
+		cancel()
+		if err != nil {
+			log.Errorf("client.Shed(%s,%d,%s) error(%v)", t.from, t.count, t.to.server, err)
+			continue
+		}
+		log.Infof("rebalance shed server:%s to:%s count:%d shed:%d", t.from, t.to.server, t.count, reply.Count)
+	}
+}
+
+// *读取服务发现中所有comet的负载,集群不健康时返回false:
+// *comet数量不足、有comet已下线或正在下线、有comet还没上报连接数、有comet没有响应最近一次统计
+func (l *Logic) cometLoads(c context.Context) (loads []*cometLoad, ok bool) {
+	nodes := l.dis.GetServiceNodes(_cometAppID)
+	if len(nodes) < l.c.Rebalance.MinNodes || len(nodes) < 2 {
+		return
+	}
+	stats := l.OnlineServers(c)
+	if len(stats) != len(nodes) {
+		log.Infof("rebalance skipped, %d of %d comets answered stats", len(stats), len(nodes))
+		return
+	}
+	for _, st := range stats {
+		if st.Stale {
+			log.Infof("rebalance skipped, comet %s did not answer the last stats", st.Server)
+			return
+		}
+	}
+	for _, nd := range nodes {
+		server := cometServerID(nd)
+		if offline, _ := strconv.ParseBool(nd.Metadata[discovery.MetaOffline]); offline {
+			log.Infof("rebalance skipped, comet %s is offline", server)
+			return
+		}
+		conns, err := strconv.ParseInt(nd.Metadata[discovery.MetaConns], 10, 64)
+		if err != nil {
+			log.Infof("rebalance skipped, comet %s has no conn count", server)
+			return
+		}
+		weight, _ := strconv.ParseInt(nd.Metadata[discovery.MetaWeight], 10, 64)
+		var addrs []string
+		if s := nd.Metadata[discovery.MetaAddrs]; s != "" {
+			addrs = strings.Split(s, ",")
+		}
+		loads = append(loads, &cometLoad{server: server, addrs: addrs, conns: conns, weight: weight})
+	}
+	return loads, true
+}
+
+// *按权重计算每个comet的目标连接数,超过目标值Threshold比例的comet转移超出部分的Fraction,
+// *每次转移到当前负载最低且低于目标值的comet,所有转移合计不超过MaxShed
+func planRebalance(loads []*cometLoad, c *conf.Rebalance) (tasks []*shedTask) {
+	var total, weights int64
+	for _, ld := range loads {
+		total += ld.conns
+		weights += cometWeight(ld)
+	}
+	if total == 0 {
+		return
+	}
+	var (
+		targets = make(map[string]float64, len(loads))
+		conns   = make(map[string]float64, len(loads))
+		over    []*cometLoad
+	)
+	for _, ld := range loads {
+		targets[ld.server] = float64(total) * float64(cometWeight(ld)) / float64(weights)
+		conns[ld.server] = float64(ld.conns)
+		if conns[ld.server] > targets[ld.server]*(1+c.Threshold) {
+			over = append(over, ld)
+		}
+	}
+	//*超出最多的先转移
+	sort.Slice(over, func(i, j int) bool {
+		return conns[over[i].server]-targets[over[i].server] > conns[over[j].server]-targets[over[j].server]
+	})
+	left := c.MaxShed
+	for _, from := range over {
+		if left <= 0 {
+			break
+		}
+		count := int((conns[from.server] - targets[from.server]) * c.Fraction)
+		if count > left {
+			count = left
+		}
+		var to *cometLoad
+		for _, ld := range loads {
+			if conns[ld.server] >= targets[ld.server] {
+				continue
+			}
+			if to == nil || conns[ld.server]/targets[ld.server] < conns[to.server]/targets[to.server] {
+				to = ld
+			}
+		}
+		if count <= 0 || to == nil {
+			continue
+		}
+		conns[from.server] -= float64(count)
+		conns[to.server] += float64(count)
+		left -= count
+		tasks = append(tasks, &shedTask{from: from.server, to: to, count: count})
+	}
+	return
+}
+
+// *comet的权重,没有配置时按1计算
+func cometWeight(ld *cometLoad) int64 {
+	if ld.weight <= 0 {
+		return 1
+	}
+	return ld.weight
+}
@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/gyy0727/mygoim/internal/logic/conf"
+)
+
+func TestPlanRebalance(t *testing.T) {
+	c := &conf.Rebalance{Threshold: 0.2, Fraction: 0.5, MaxShed: 1000}
+	loads := []*cometLoad{
+		{server: "a", conns: 900},
+		{server: "b", conns: 300},
+		{server: "c", conns: 0, addrs: []string{"10.0.0.3"}},
+	}
+	tasks := planRebalance(loads, c)
+	// 目标值都是400,a超出500转移一半到负载最低的c
+	if len(tasks) != 1 {
+		t.Fatalf("tasks %d want 1", len(tasks))
+	}
+	if tk := tasks[0]; tk.from != "a" || tk.to.server != "c" || tk.count != 250 {
+		t.Fatalf("task from:%s to:%s count:%d", tk.from, tk.to.server, tk.count)
+	}
+	// 受MaxShed限制
+	c.MaxShed = 100
+	if tasks = planRebalance(loads, c); len(tasks) != 1 || tasks[0].count != 100 {
+		t.Fatalf("tasks %+v want one task of 100", tasks)
+	}
+	// 按权重计算目标值,a的权重是b的3倍,没有过载
+	c.MaxShed = 1000
+	loads = []*cometLoad{
+		{server: "a", conns: 900, weight: 3},
+		{server: "b", conns: 300, weight: 1},
+	}
+	if tasks = planRebalance(loads, c); len(tasks) != 0 {
+		t.Fatalf("tasks %d want 0", len(tasks))
+	}
+	// 没有超过阈值时不转移
+	loads = []*cometLoad{
+		{server: "a", conns: 110},
+		{server: "b", conns: 90},
+	}
+	if tasks = planRebalance(loads, c); len(tasks) != 0 {
+		t.Fatalf("tasks %d want 0", len(tasks))
+	}
+}
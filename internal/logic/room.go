@@ -0,0 +1,143 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/comet"
+	"github.com/gyy0727/mygoim/api/protocol"
+	"github.com/gyy0727/mygoim/internal/logic/model"
+)
+
+// *校验用户能否加入房间,room为房间键,ticket为客户端携带的密码或票据
+func (l *Logic) AuthorizeRoom(c context.Context, mid int64, key, server, room, ticket string) (code int32, err error) {
+	if room == "" {
+		return protocol.RoomAuthOK, nil
+	}
+	typ, _, err := model.DecodeRoomKey(room)
+	if err != nil {
+		return protocol.RoomAuthFailed, nil
+	}
+	rule, secret := l.c.Room.Policy(typ)
+	switch rule {
+	case "", "open":
+		code = protocol.RoomAuthOK
+	case "allow":
+		var ok bool
+		if mid > 0 {
+			if ok, err = l.dao.RoomAllowed(c, room, mid); err != nil {
+				return protocol.RoomAuthFailed, err
+			}
+		}
+		if code = protocol.RoomAuthOK; !ok {
+			code = protocol.RoomAuthDenied
+		}
+	case "password":
+		var password string
+		if password, err = l.dao.RoomPassword(c, room); err != nil {
+			return protocol.RoomAuthFailed, err
+		}
+		//*没有设置密码的房间不需要校验
+		if code = protocol.RoomAuthOK; password != "" && subtle.ConstantTimeCompare([]byte(password), []byte(ticket)) != 1 {
+			code = protocol.RoomAuthTicket
+		}
+	case "ticket":
+		if code = protocol.RoomAuthOK; secret == "" || !verifyRoomTicket(secret, room, mid, ticket) {
+			code = protocol.RoomAuthTicket
+		}
+	default:
+		log.Errorf("unknown room rule:%s type:%s", rule, typ)
+		code = protocol.RoomAuthFailed
+	}
+	if code != protocol.RoomAuthOK {
+		log.Infof("authorize room denied mid:%d key:%s server:%s room:%s rule:%s code:%d", mid, key, server, room, rule, code)
+	}
+	return
+}
+
+// *汇总所有comet上房间中的连接,按key排序后分页,查询失败的comet放在Failed中
+func (l *Logic) RoomMembers(c context.Context, room string, offset, limit int) (res *model.RoomMembers, err error) {
+	if offset < 0 || limit <= 0 || offset > math.MaxInt32-limit {
+		err = fmt.Errorf("invalid room members page offset:%d limit:%d", offset, limit)
+		return
+	}
+	var (
+		mutex sync.Mutex
+		// *每个comet只需要返回前offset+limit个,合并排序后仍然是全局的前offset+limit个
+		req = &comet.RoomMembersReq{RoomID: room, Limit: int32(offset + limit)}
+	)
+	res = &model.RoomMembers{Members: []*model.RoomMember{}, Failed: []string{}}
+	l.eachComet(c, func(ctx context.Context, server string, client comet.CometClient) error {
+		reply, err := client.RoomMembers(ctx, req)
+		if err != nil {
+			mutex.Lock()
+			res.Failed = append(res.Failed, server)
+			mutex.Unlock()
+			return err
+		}
+		mutex.Lock()
+		res.Total += int(reply.Total)
+		for _, m := range reply.Members {
+			res.Members = append(res.Members, &model.RoomMember{Mid: m.Mid, Key: m.Key, Server: server})
+		}
+		mutex.Unlock()
+		return nil
+	})
+	sort.Strings(res.Failed)
+	sort.Slice(res.Members, func(i, j int) bool { return res.Members[i].Key < res.Members[j].Key })
+	if offset >= len(res.Members) {
+		res.Members = res.Members[:0]
+		return
+	}
+	res.Members = res.Members[offset:]
+	if limit < len(res.Members) {
+		res.Members = res.Members[:limit]
+	}
+	return
+}
+
+// *把用户加入房间的允许名单
+func (l *Logic) AddRoomAllow(c context.Context, room string, mids []int64) error {
+	return l.dao.AddRoomAllow(c, room, mids)
+}
+
+// *把用户移出房间的允许名单
+func (l *Logic) DelRoomAllow(c context.Context, room string, mids []int64) error {
+	return l.dao.DelRoomAllow(c, room, mids)
+}
+
+// *设置房间的密码
+func (l *Logic) SetRoomPassword(c context.Context, room, password string) error {
+	return l.dao.SetRoomPassword(c, room, password)
+}
+
+// *生成房间票据: 过期时间.hex(hmac-sha256(房间键:用户ID:过期时间))
+func roomTicket(secret, room string, mid, expire int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s:%d:%d", room, mid, expire)
+	return strconv.FormatInt(expire, 10) + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// *校验房间票据的签名和有效期
+func verifyRoomTicket(secret, room string, mid int64, ticket string) bool {
+	i := strings.IndexByte(ticket, '.')
+	if i <= 0 {
+		return false
+	}
+	expire, err := strconv.ParseInt(ticket[:i], 10, 64)
+	if err != nil || time.Now().Unix() > expire {
+		return false
+	}
+	return hmac.Equal([]byte(ticket), []byte(roomTicket(secret, room, mid, expire)))
+}
@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/protocol"
+)
+
+// *单推消息的推送目标,序列号不同的消息不能合并到同一个PushMsg里
+type pushTarget struct {
+	server string
+	seq    int32
+}
+
+// *为用户分配消息序列号,未开启或分配失败时返回nil,消息照常推送但不带序列号
+func (l *Logic) midSeqs(c context.Context, op int32, mids []int64, msg []byte) map[int64]int32 {
+	if l.c.Seq == nil || !l.c.Seq.Open {
+		return nil
+	}
+	var (
+		ids  []int64
+		seen = make(map[int64]struct{}, len(mids))
+	)
+	for _, mid := range mids {
+		if _, ok := seen[mid]; ok || mid <= 0 {
+			continue
+		}
+		seen[mid] = struct{}{}
+		ids = append(ids, mid)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	seqs, err := l.dao.AddSeqMsgs(c, ids, op, msg, l.c.Seq.Max, time.Duration(l.c.Seq.Expire))
+	if err != nil {
+		log.Errorf("l.dao.AddSeqMsgs(%v,%d) error(%v)", ids, op, err)
+		return nil
+	}
+	return seqs
+}
+
+// *为keys对应的用户分配消息序列号,返回每个key的序列号,匿名连接为0
+func (l *Logic) keySeqs(c context.Context, op int32, keys []string, msg []byte) []int32 {
+	if l.c.Seq == nil || !l.c.Seq.Open {
+		return nil
+	}
+	mids, err := l.dao.MidsByKeys(c, keys)
+	if err != nil {
+		return nil
+	}
+	midSeqs := l.midSeqs(c, op, mids, msg)
+	if midSeqs == nil {
+		return nil
+	}
+	seqs := make([]int32, len(keys))
+	for i, mid := range mids {
+		seqs[i] = midSeqs[mid]
+	}
+	return seqs
+}
+
+// *返回用户序列号大于seq的消息,供重连的客户端补齐错过的消息
+func (l *Logic) Sync(c context.Context, mid int64, key, server string, seq int32) (protos []*protocol.Proto, err error) {
+	if l.c.Seq == nil || !l.c.Seq.Open || mid <= 0 {
+		return
+	}
+	msgs, err := l.dao.SeqMsgs(c, mid, seq, l.c.Seq.Sync)
+	if err != nil {
+		return
+	}
+	for _, msg := range msgs {
+		protos = append(protos, &protocol.Proto{Ver: 1, Op: msg.Op, Seq: msg.Seq, Body: msg.Msg})
+	}
+	log.Infof("sync mid:%d key:%s server:%s seq:%d count:%d", mid, key, server, seq, len(protos))
+	return
+}
@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	log "github.com/golang/glog"
+	"github.com/gyy0727/mygoim/api/comet"
+)
+
+// *向所有comet添加白名单,ttl为秒,<=0时不过期,返回添加成功的comet
+func (l *Logic) AddWhitelist(c context.Context, mids []int64, prefixes []string, sample int32, ttl int64) []string {
+	req := &comet.WhitelistReq{Mids: mids, Prefixes: prefixes, Sample: sample, Ttl: ttl}
+	servers := l.whitelist(c, func(ctx context.Context, client comet.CometClient) error {
+		_, err := client.AddWhitelist(ctx, req)
+		return err
+	})
+	log.Infof("add whitelist mids:%v prefixes:%v sample:%d ttl:%d servers:%v", mids, prefixes, sample, ttl, servers)
+	return servers
+}
+
+// *从所有comet移除白名单,返回移除成功的comet
+func (l *Logic) DelWhitelist(c context.Context, mids []int64, prefixes []string) []string {
+	req := &comet.WhitelistReq{Mids: mids, Prefixes: prefixes}
+	servers := l.whitelist(c, func(ctx context.Context, client comet.CometClient) error {
+		_, err := client.DelWhitelist(ctx, req)
+		return err
+	})
+	log.Infof("del whitelist mids:%v prefixes:%v servers:%v", mids, prefixes, servers)
+	return servers
+}
+
+func (l *Logic) whitelist(c context.Context, fn func(ctx context.Context, client comet.CometClient) error) (servers []string) {
+	var mutex sync.Mutex
+	servers = []string{}
+	l.eachComet(c, func(ctx context.Context, server string, client comet.CometClient) error {
+		if err := fn(ctx, client); err != nil {
+			return err
+		}
+		mutex.Lock()
+		servers = append(servers, server)
+		mutex.Unlock()
+		return nil
+	})
+	sort.Strings(servers)
+	return
+}
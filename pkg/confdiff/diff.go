@@ -0,0 +1,94 @@
+package confdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// *字段名包含这些词或等于auth时不输出具体值,避免密码和密钥写进日志
+var secretWords = []string{"password", "secret", "token", "key"}
+
+// *比较两份配置,返回发生变化的字段,格式为 "Section.Field: old -> new"
+func Diff(old, new interface{}) (diffs []string, err error) {
+	var (
+		oldFields = make(map[string]string)
+		newFields = make(map[string]string)
+	)
+	if err = flatten(old, oldFields); err != nil {
+		return
+	}
+	if err = flatten(new, newFields); err != nil {
+		return
+	}
+	paths := make(map[string]struct{}, len(newFields))
+	for path := range oldFields {
+		paths[path] = struct{}{}
+	}
+	for path := range newFields {
+		paths[path] = struct{}{}
+	}
+	for path := range paths {
+		ov, oldOK := oldFields[path]
+		nv, newOK := newFields[path]
+		if oldOK && newOK && ov == nv {
+			continue
+		}
+		if !oldOK {
+			ov = "<nil>"
+		}
+		if !newOK {
+			nv = "<nil>"
+		}
+		if isSecret(path) {
+			diffs = append(diffs, path+": changed")
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: %s -> %s", path, ov, nv))
+	}
+	sort.Strings(diffs)
+	return
+}
+
+// *把配置展开成 路径->JSON值,数组作为一个整体比较
+func flatten(v interface{}, fields map[string]string) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var m interface{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	walk("", m, fields)
+	return nil
+}
+
+func walk(prefix string, v interface{}, fields map[string]string) {
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, vv := range m {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			walk(path, vv, fields)
+		}
+		return
+	}
+	b, _ := json.Marshal(v)
+	fields[prefix] = string(b)
+}
+
+func isSecret(path string) bool {
+	name := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	if name == "auth" {
+		return true
+	}
+	for _, w := range secretWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+	return false
+}
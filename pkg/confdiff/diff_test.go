@@ -0,0 +1,47 @@
+package confdiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testSection struct {
+	Timeout  int
+	Password string
+	List     []int64
+}
+
+type testConfig struct {
+	Debug   bool
+	Section *testSection
+	Regions map[string][]string
+}
+
+func TestDiff(t *testing.T) {
+	old := &testConfig{
+		Section: &testSection{Timeout: 5, Password: "a", List: []int64{1}},
+		Regions: map[string][]string{"sh": {"上海"}},
+	}
+	new := &testConfig{
+		Debug:   true,
+		Section: &testSection{Timeout: 5, Password: "b", List: []int64{1, 2}},
+		Regions: map[string][]string{"bj": {"北京"}},
+	}
+	diffs, err := Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Debug: false -> true",
+		`Regions.bj: <nil> -> ["北京"]`,
+		`Regions.sh: ["上海"] -> <nil>`,
+		"Section.List: [1] -> [1,2]",
+		"Section.Password: changed",
+	}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Fatalf("diffs %q; want %q", diffs, want)
+	}
+	if diffs, _ = Diff(old, old); len(diffs) != 0 {
+		t.Fatalf("diffs %q; want empty", diffs)
+	}
+}
@@ -278,23 +278,9 @@ func (e *EtcdResolver) watchNode(ctx context.Context, node *Node) {
 					}
 					e.setServiceNodes(node.Name, n)
 				case etcdV3.EventTypeDelete:
-					name, addr, err := node.SplitPath(event.Kv.String())
-					if err != nil {
-						panic("解析节点错误")
-
-					}
-					e.removeServiceNode(name, addr)
-					rsp, err := e.cli.Get(cctx, node.buildPrefix(), etcdV3.WithPrefix())
-					if err != nil {
-						log.Errorf("get keys for prefix %s error:%s", node.buildPrefix(), err.Error())
-						continue
-					}
-					if len(rsp.Kvs) == 0 {
-						//*如果所有相关键都被删除，取消协程
-						cancel()
-						log.Infof("node watcher for %s exited due to all keys deletion", node.Name)
-						return
-					}
+					//*key 的格式为 前缀/地址
+					addr := strings.TrimPrefix(string(event.Kv.Key), node.buildPrefix()+"/")
+					e.removeServiceNode(node.Name, addr)
 				}
 			}
 		case <-ctx.Done():
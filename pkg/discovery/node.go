@@ -5,9 +5,19 @@ import (
 	"strings"
 )
 
+// *节点元数据中约定的key
+const (
+	MetaHostname = "hostname"  //*节点的主机名,comet用它作为serverID
+	MetaWeight   = "weight"    //*负载均衡权重
+	MetaOffline  = "offline"   //*是否已下线,不再分配新连接
+	MetaAddrs    = "addrs"     //*客户端连接用的公网地址,逗号分隔
+	MetaConns    = "connCount" //*当前的连接数,comet定期更新
+)
+
 type Node struct {
-	Name string `json:"name"` //*名称
-	Addr string `json:"addr"` //*地址
+	Name     string            `json:"name"`               //*名称
+	Addr     string            `json:"addr"`               //*地址
+	Metadata map[string]string `json:"metadata,omitempty"` //*元数据
 }
 
 // *把服务名中的 . 转换为 /
@@ -20,6 +30,11 @@ func (s Node) buildKey() string {
 	return fmt.Sprintf("/%s/%s", s.transName(), s.Addr)
 }
 
+// *返回节点在etcd中的key
+func (s Node) Key() string {
+	return s.buildKey()
+}
+
 // *构建节点前缀
 func (s Node) buildPrefix() string {
 	return fmt.Sprintf("/%s", s.transName())